@@ -0,0 +1,111 @@
+package socialrecon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// printResult renders a result as a tree: findings about the target itself,
+// then every identity with where it was found and its findings, then the
+// inconclusive checks
+func printResult(result *models.ScanResult) {
+	if len(result.Findings) > 0 {
+		printFindings(result.Findings)
+	}
+
+	for _, id := range result.Identities {
+		fmt.Println()
+		color.New(color.FgHiWhite, color.Bold).Printf("👤 %s", id.Username)
+		switch id.Ownership {
+		case models.OwnershipVerified:
+			color.HiGreen(" ✔ verified")
+		case models.OwnershipOneWay:
+			color.Yellow(" ⚠ claimed one way")
+		case models.OwnershipUnrelated:
+			color.HiBlack(" ✘ unrelated")
+		default:
+			fmt.Println()
+		}
+		if p := id.Permutation; p != nil {
+			color.HiBlack("   ↳ lookalike of %s (%s)", p.Of, p.Detail)
+		}
+		for _, src := range id.Sources {
+			line := "   ↳ " + src.URL
+			if n := len(src.Redirects); n > 0 {
+				line += " → " + src.Redirects[n-1]
+			}
+			switch {
+			case src.Via != "" && src.Page != "":
+				line += " (" + src.Via + " on " + src.Page + ")"
+			case src.Page != "":
+				line += " (on " + src.Page + ")"
+			}
+			color.HiBlack(line)
+		}
+		if len(id.Findings) == 0 && len(id.Executions) == 0 {
+			color.HiBlack("   No plugin checks this handle's platform")
+			continue
+		}
+		printFindings(id.Findings)
+	}
+	if len(result.Identities) == 0 && len(result.Findings) == 0 {
+		color.HiBlack("\nNo social identities discovered")
+	}
+
+	printInconclusive(result)
+}
+
+// printFindings renders a findings table
+func printFindings(findings []models.Finding) {
+	fmt.Printf("\n%-12s | %-15s | %-12s | %s\n", "PLATFORM", "STATUS", "SEVERITY", "FINDING")
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, f := range findings {
+		statusColor := color.New(color.FgCyan).SprintFunc()
+		switch f.Status {
+		case models.StatusAvailable:
+			statusColor = color.New(color.FgHiGreen, color.Bold).SprintFunc()
+		case models.StatusSuspended, models.StatusDeactivated, models.StatusRestricted, models.StatusAbandoned:
+			statusColor = color.New(color.FgYellow).SprintFunc()
+		case models.StatusRateLimited, models.StatusError:
+			statusColor = color.New(color.FgMagenta).SprintFunc()
+		case models.StatusUnknown, models.StatusInvalidUsername:
+			statusColor = color.New(color.FgHiBlack).SprintFunc()
+		}
+
+		sevColor := color.New(color.FgBlue).SprintFunc()
+		switch f.Severity {
+		case models.SeverityHigh, models.SeverityCritical:
+			sevColor = color.New(color.FgRed, color.Bold).SprintFunc()
+		case models.SeverityMedium:
+			sevColor = color.New(color.FgYellow).SprintFunc()
+		}
+
+		fmt.Printf("%-12s | %-15s | %-12s | %s\n",
+			f.PluginName,
+			statusColor(f.Status),
+			sevColor(f.Severity),
+			f.Description,
+		)
+	}
+}
+
+// printInconclusive lists the checks of a result that gave no answer
+func printInconclusive(result *models.ScanResult) {
+	if inconclusive := result.Inconclusive(); len(inconclusive) > 0 {
+		fmt.Println()
+		color.Yellow("⚠️  Inconclusive checks")
+		fmt.Printf("\n%-12s | %-15s | %-14s | %s\n", "PLATFORM", "TARGET", "REASON", "DETAILS")
+		fmt.Println(strings.Repeat("-", 80))
+		for _, e := range inconclusive {
+			reason := e.ErrorClass
+			if reason == "" {
+				reason = string(e.Outcome)
+			}
+			fmt.Printf("%-12s | %-15s | %-14s | %s\n", e.Plugin, e.Target, reason, e.Message)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package socialrecon
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/ismailtsdln/socialrecon/internal/engine"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/schollz/progressbar/v3"
+)
+
+// progressObserver renders live scan progress from engine events. In
+// verbose mode every conclusive finding is printed as it arrives.
+type progressObserver struct {
+	bar *progressbar.ProgressBar
+}
+
+func newProgressObserver(total int) *progressObserver {
+	return &progressObserver{
+		bar: progressbar.NewOptions(total,
+			progressbar.OptionSetWriter(os.Stderr),
+			progressbar.OptionSetDescription("Checking platforms"),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetWidth(30),
+			progressbar.OptionClearOnFinish(),
+		),
+	}
+}
+
+func (p *progressObserver) OnEvent(ev engine.Event) {
+	switch ev.Type {
+	case engine.EventScanStarted:
+		if verbose {
+			p.println(fmt.Sprintf("   -> Scanning username: %s", ev.Target))
+		}
+	case engine.EventFinding:
+		if verbose && ev.Finding.Status.Conclusive() && ev.Finding.Status != models.StatusInvalidUsername {
+			p.println(fmt.Sprintf("      [%s] %s", ev.Plugin, ev.Finding.Description))
+		}
+	case engine.EventError:
+		if verbose {
+			p.println(color.YellowString("      [%s] %v", ev.Plugin, ev.Err))
+		}
+	case engine.EventPluginFinished:
+		p.bar.Add(1)
+	}
+}
+
+// println prints a line above the progress bar
+func (p *progressObserver) println(line string) {
+	p.bar.Clear()
+	fmt.Fprintln(os.Stderr, line)
+	p.bar.RenderBlank()
+}
@@ -2,20 +2,35 @@ package socialrecon
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/ismailtsdln/socialrecon/internal/activity"
+	"github.com/ismailtsdln/socialrecon/internal/avatar"
+	"github.com/ismailtsdln/socialrecon/internal/confusables"
 	"github.com/ismailtsdln/socialrecon/internal/engine"
+	"github.com/ismailtsdln/socialrecon/internal/graph"
+	"github.com/ismailtsdln/socialrecon/internal/hijack"
 	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/ownership"
+	"github.com/ismailtsdln/socialrecon/internal/permute"
 	"github.com/ismailtsdln/socialrecon/internal/plugins"
-	"github.com/ismailtsdln/socialrecon/internal/plugins/github"
-	"github.com/ismailtsdln/socialrecon/internal/plugins/instagram"
-	"github.com/ismailtsdln/socialrecon/internal/plugins/twitter"
+	"github.com/ismailtsdln/socialrecon/internal/plugins/manifest"
 	"github.com/ismailtsdln/socialrecon/internal/report"
 	"github.com/ismailtsdln/socialrecon/internal/scanner"
 	"github.com/ismailtsdln/socialrecon/internal/scoring"
+	"github.com/ismailtsdln/socialrecon/internal/similarity"
+	"github.com/ismailtsdln/socialrecon/internal/targets"
 	"github.com/spf13/cobra"
 )
 
@@ -27,18 +42,46 @@ var (
 	}
 
 	scanCmd = &cobra.Command{
-		Use:   "scan [target]",
-		Short: "Scan a domain, brand, or username",
-		Args:  cobra.MinimumNArgs(1),
-		RunE:  runScan,
+		Use:   "scan [target...]",
+		Short: "Scan domains, brands, or usernames",
+		Long: `Scan one or more domains, brands, or usernames.
+
+Targets are taken from the arguments and from --targets-file, which holds one
+target per line or CSV with "target" and "type" (domain, username, auto)
+columns. Use --targets-file - to read from stdin.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runScan,
 	}
 
 	// Flags
-	jsonOutput bool
-	htmlReport string
-	verbose    bool
+	jsonOutput  bool
+	htmlReport  string
+	verbose     bool
+	siteFiles   []string
+	rate        float64
+	platRates   map[string]string
+	attempts    int
+	targetsFile string
+	concurrency int
+	timeout     time.Duration
+	crawlDepth  int
+	crawlPages  int
+	noRobots    bool
+	crossPlat   bool
+	expandLinks bool
+	permutate   bool
+	homoglyphs  bool
+	maxPerms    int
+	staleAfter  int
+	avatars     bool
+	brandLogos  []string
+	avatarDist  int
+	graphFile   string
 )
 
+// discoveryPlugin names the site crawl in the executions it records
+const discoveryPlugin = "Link discovery"
+
 const banner = `
    _____            _       _______                     
   / ___/____  _____(_)___ _/ / ___/___  _________  ____ 
@@ -55,7 +98,27 @@ func PrintBanner() {
 func init() {
 	scanCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results in JSON format")
 	scanCmd.Flags().StringVar(&htmlReport, "html-report", "", "Path to save HTML report")
+	scanCmd.Flags().StringVar(&graphFile, "graph", "", "Path to save the identity graph (.json, .graphml, .dot or .gv)")
 	scanCmd.Flags().BoolVar(&verbose, "verbose", false, "Enable verbose output")
+	scanCmd.Flags().StringVarP(&targetsFile, "targets-file", "f", "", "Read targets from a file, one per line or CSV ('-' for stdin)")
+	scanCmd.Flags().IntVar(&concurrency, "concurrency", 10, "Maximum platform checks in flight across all targets")
+	scanCmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute, "Time limit for scanning a single username (0 = none)")
+	scanCmd.Flags().IntVar(&crawlDepth, "crawl-depth", scanner.DefaultCrawlOptions().MaxDepth, "Link hops followed from a domain's landing page (0 = landing page only)")
+	scanCmd.Flags().IntVar(&crawlPages, "crawl-pages", scanner.DefaultCrawlOptions().MaxPages, "Maximum pages fetched per domain")
+	scanCmd.Flags().BoolVar(&noRobots, "ignore-robots", false, "Crawl pages disallowed by robots.txt")
+	scanCmd.Flags().BoolVar(&expandLinks, "expand-links", false, "Follow shortened links (bit.ly, t.co, ...) found on domains to the profiles behind them")
+	scanCmd.Flags().BoolVar(&crossPlat, "cross-platform", false, "Check handles discovered on a domain on every platform, not only the one they were linked from")
+	scanCmd.Flags().BoolVar(&permutate, "permutations", false, "Also check lookalike handles of username targets for impersonation")
+	scanCmd.Flags().BoolVar(&homoglyphs, "homoglyphs", false, "Also check Unicode homoglyph variants of username targets on platforms that allow them")
+	scanCmd.Flags().IntVar(&maxPerms, "max-permutations", permute.DefaultMax, "Maximum lookalike handles checked per username target and kind")
+	scanCmd.Flags().IntVar(&staleAfter, "stale-after", activity.DefaultMonths, "Months without activity after which an official account is reported abandoned (0 = never)")
+	scanCmd.Flags().BoolVar(&avatars, "avatars", false, "Download profile avatars and compute their perceptual hashes")
+	scanCmd.Flags().StringSliceVar(&brandLogos, "brand-logo", nil, "Brand image (PNG, JPEG or GIF) lookalike avatars are compared with; implies --avatars")
+	scanCmd.Flags().IntVar(&avatarDist, "avatar-threshold", avatar.DefaultThreshold, "Maximum hash distance, out of 64 bits, at which an avatar copies a brand image")
+	scanCmd.Flags().StringSliceVar(&siteFiles, "sites", nil, "Additional site manifest files (YAML or JSON)")
+	scanCmd.Flags().Float64Var(&rate, "rate", 2, "Maximum requests per second per host (0 = unlimited)")
+	scanCmd.Flags().IntVar(&attempts, "max-attempts", 3, "Attempts per request for transient errors (429, 5xx, timeouts)")
+	scanCmd.Flags().StringToStringVar(&platRates, "platform-rate", nil, "Per-platform rate overrides, e.g. github=0.5,instagram=1")
 	rootCmd.AddCommand(scanCmd)
 }
 
@@ -64,117 +127,433 @@ func Execute() error {
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
-	target := args[0]
+	list, err := loadTargets(args, targetsFile)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return fmt.Errorf("no targets given: pass them as arguments or with --targets-file")
+	}
 
 	if !jsonOutput {
 		PrintBanner()
-		color.Cyan("🚀 Starting SocialRecon scan for: %s", target)
+		if len(list) == 1 {
+			color.Cyan("🚀 Starting SocialRecon scan for: %s", list[0].Value)
+		} else {
+			color.Cyan("🚀 Starting SocialRecon batch scan of %d targets", len(list))
+		}
+	}
+
+	pluginRates := make(map[string]float64, len(platRates))
+	for name, v := range platRates {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid --platform-rate for %s: %q", name, v)
+		}
+		pluginRates[name] = r
 	}
 
 	cfg := models.Config{
-		MaxConcurrency: 10,
-		Timeout:        30 * time.Second,
+		MaxConcurrency: concurrency,
+		Timeout:        timeout,
+		RateLimit:      rate,
+		PluginRates:    pluginRates,
+		MaxAttempts:    attempts,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
-	defer cancel()
+	refs, err := avatar.LoadReferences(brandLogos...)
+	if err != nil {
+		return fmt.Errorf("failed to load brand logos: %w", err)
+	}
 
-	// 1. Initial Discovery (if target is a domain)
-	foundUsernames := make(map[string]bool)
-	isDomain := strings.Contains(target, ".") || strings.HasPrefix(target, "http")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	if isDomain {
-		extractor := scanner.NewExtractor()
-		if !jsonOutput {
-			color.Yellow("🔍 Extracting social links from domain...")
-		}
-		links, err := extractor.ExtractSocialLinks(target)
-		if err == nil {
-			for _, l := range links {
-				foundUsernames[l.Username] = true
+	// 1. Initial Discovery (for domain targets)
+	identities, failures := discoverIdentities(ctx, list, cfg.MaxConcurrency)
+	if permutate {
+		for i, t := range list {
+			if t.Type == models.TargetUsername {
+				identities[i] = append(identities[i], lookalikes(t.Value)...)
 			}
 		}
-	} else {
-		foundUsernames[target] = true
 	}
 
 	// 2. Setup Plugins & Engine
-	enabledPlugins := []plugins.Plugin{
-		github.NewPlugin(),
-		twitter.NewPlugin(),
-		instagram.NewPlugin(),
+	enabledPlugins, err := manifest.LoadPlugins(siteFiles...)
+	if err != nil {
+		return fmt.Errorf("failed to load site manifests: %w", err)
 	}
 	eng := engine.NewEngine(cfg, enabledPlugins)
-
-	// 3. Run Scanning for each found username
-	finalResult := &models.ScanResult{
-		Target:    target,
-		Findings:  []models.Finding{},
-		StartTime: time.Now(),
+	var matcher *avatar.Matcher
+	if avatars || len(refs) > 0 {
+		matcher = avatar.NewMatcher(eng.Client(), refs, avatarDist)
 	}
-
-	for username := range foundUsernames {
-		if !jsonOutput && verbose {
-			fmt.Printf("   -> Scanning username: %s\n", username)
+	if homoglyphs {
+		for i, t := range list {
+			if t.Type == models.TargetUsername {
+				identities[i] = append(identities[i], homoglyphVariants(t.Value, eng.Plugins())...)
+			}
 		}
-		res, err := eng.Run(ctx, username)
-		if err != nil {
-			if !jsonOutput {
-				color.Red("   ❌ Error scanning %s: %v", username, err)
+	}
+
+	// 3. Scan every distinct username once through the shared worker pool.
+	// Discovered handles are only checked on the platforms they were linked
+	// from unless --cross-platform is set.
+	var jobs []engine.Job
+	index := make(map[string]int)
+	for _, ids := range identities {
+		for _, id := range ids {
+			platforms := checkedPlatforms(id)
+			i, ok := index[id.Username]
+			if !ok {
+				index[id.Username] = len(jobs)
+				jobs = append(jobs, engine.Job{Target: id.Username, Plugins: platforms})
+				continue
+			}
+			if jobs[i].Plugins != nil {
+				if platforms == nil {
+					jobs[i].Plugins = nil
+				} else {
+					jobs[i].Plugins = mergeNames(jobs[i].Plugins, platforms)
+				}
 			}
 		}
-		if res != nil {
-			finalResult.Findings = append(finalResult.Findings, res.Findings...)
+	}
+
+	if !jsonOutput {
+		total := 0
+		for _, job := range jobs {
+			total += len(eng.Plugins(job.Plugins...))
 		}
+		eng.Subscribe(newProgressObserver(total))
 	}
 
-	finalResult.EndTime = time.Now()
+	batch := &models.BatchResult{StartTime: time.Now()}
+	scanned, err := eng.RunJobs(ctx, jobs)
+	if err != nil && !jsonOutput {
+		var runErr *engine.RunError
+		if errors.As(err, &runErr) {
+			color.Yellow("⚠️  %d check(s) failed", len(runErr.Errors))
+		} else {
+			color.Red("❌ Scan error: %v", err)
+		}
+	}
+	byUsername := make(map[string]*models.ScanResult, len(scanned))
+	for _, res := range scanned {
+		byUsername[res.Target] = res
+	}
 
-	// 4. Calculate risk score
+	// 4. Attach findings to each target's identities and calculate risk scores
 	scorer := scoring.NewScoringEngine()
-	finalResult.RiskScore = scorer.Calculate(finalResult)
+	for i, t := range list {
+		result := &models.ScanResult{
+			Target:     t.Value,
+			TargetType: t.Type,
+			Findings:   []models.Finding{},
+			StartTime:  batch.StartTime,
+		}
+		if failures[i] != nil {
+			result.Executions = append(result.Executions, *failures[i])
+		}
+		for _, id := range identities[i] {
+			id.Findings = []models.Finding{}
+			if res := byUsername[id.Username]; res != nil {
+				// Copy so identities shared between targets are scored
+				// independently; a handle scanned for several identities
+				// only reports the platforms each one is checked on
+				platforms := checkedPlatforms(id)
+				for _, f := range res.Findings {
+					// A lookalike the platform cannot have is no candidate
+					if id.Permutation != nil && f.Status == models.StatusInvalidUsername {
+						continue
+					}
+					if platforms == nil || containsFold(platforms, f.PluginName) {
+						id.Findings = append(id.Findings, f)
+					}
+				}
+				for _, ex := range res.Executions {
+					if platforms == nil || containsFold(platforms, ex.Plugin) {
+						id.Executions = append(id.Executions, ex)
+					}
+				}
+				permute.Tag(&id)
+				activity.NewPolicy(staleAfter).Apply(&id)
+				if t.Type == models.TargetDomain {
+					ownership.Verify(t.Value, &id)
+				}
+				id.Findings = append(id.Findings, confusables.Detect(brand(t), id)...)
+				similarity.Annotate(&id)
+				if matcher != nil {
+					matcher.Annotate(ctx, &id)
+				}
+				id.Findings = append(id.Findings, hijack.Detect(id)...)
+				if res.EndTime.After(result.EndTime) {
+					result.EndTime = res.EndTime
+				}
+			}
+			result.Identities = append(result.Identities, id)
+		}
+		if result.EndTime.IsZero() {
+			result.EndTime = time.Now()
+		}
+		result.Graph = graph.Build(result)
+		result.RiskScore = scorer.Calculate(result)
+		if result.RiskScore > batch.RiskScore {
+			batch.RiskScore = result.RiskScore
+		}
+		batch.Results = append(batch.Results, result)
+	}
+	batch.EndTime = time.Now()
 
 	reporter := report.NewReporter()
-	if jsonOutput {
-		reporter.ExportJSON(finalResult, "")
+	if len(batch.Results) == 1 {
+		finalResult := batch.Results[0]
+		if jsonOutput {
+			reporter.ExportJSON(finalResult, "")
+		} else {
+			fmt.Println()
+			color.HiGreen("✅ Scan completed in %v", finalResult.EndTime.Sub(finalResult.StartTime))
+			printResult(finalResult)
+			reporter.PrintSummary(finalResult)
+		}
 	} else {
-		fmt.Println()
-		color.HiGreen("✅ Scan completed in %v", finalResult.EndTime.Sub(finalResult.StartTime))
+		if jsonOutput {
+			reporter.ExportBatchJSON(batch, "")
+		} else {
+			fmt.Println()
+			color.HiGreen("✅ Batch scan of %d targets completed in %v", len(batch.Results), batch.EndTime.Sub(batch.StartTime))
+			for _, res := range batch.Results {
+				fmt.Println()
+				color.HiCyan("== %s (%s) ==", res.Target, res.TargetType)
+				printResult(res)
+			}
+			reporter.PrintBatchSummary(batch)
+		}
+	}
 
-		fmt.Printf("\n%-12s | %-15s | %-12s | %s\n", "PLATFORM", "STATUS", "SEVERITY", "FINDING")
-		fmt.Println(strings.Repeat("-", 80))
+	if htmlReport != "" {
+		if err := reporter.ExportBatchHTML(batch, htmlReport); err != nil {
+			return fmt.Errorf("failed to save HTML report: %w", err)
+		}
+		if !jsonOutput {
+			color.Cyan("📊 HTML report saved to: %s", htmlReport)
+		}
+	}
 
-		for _, f := range finalResult.Findings {
-			statusColor := color.New(color.FgCyan).SprintFunc()
-			if f.Status == "available" {
-				statusColor = color.New(color.FgHiGreen, color.Bold).SprintFunc()
-			}
+	if graphFile != "" {
+		graphs := make([]*models.Graph, len(batch.Results))
+		for i, res := range batch.Results {
+			graphs[i] = res.Graph
+		}
+		if err := graph.Export(graph.Merge(graphs...), graphFile); err != nil {
+			return fmt.Errorf("failed to save identity graph: %w", err)
+		}
+		if !jsonOutput {
+			color.Cyan("🕸️  Identity graph saved to: %s", graphFile)
+		}
+	}
 
-			sevColor := color.New(color.FgBlue).SprintFunc()
-			switch f.Severity {
-			case models.SeverityHigh, models.SeverityCritical:
-				sevColor = color.New(color.FgRed, color.Bold).SprintFunc()
-			case models.SeverityMedium:
-				sevColor = color.New(color.FgYellow).SprintFunc()
-			}
+	return nil
+}
 
-			fmt.Printf("%-12s | %-15s | %-12s | %s\n",
-				f.PluginName,
-				statusColor(f.Status),
-				sevColor(f.Severity),
-				f.Description,
-			)
+// checkedPlatforms returns the platforms an identity is verified on: those
+// it was linked from, or nil for every platform when the handle was given
+// directly or --cross-platform is set
+func checkedPlatforms(id models.Identity) []string {
+	if crossPlat || len(id.Sources) == 0 {
+		return nil
+	}
+	var platforms []string
+	for _, src := range id.Sources {
+		if src.Platform != "" && !containsFold(platforms, src.Platform) {
+			platforms = append(platforms, src.Platform)
 		}
+	}
+	return platforms
+}
 
-		reporter.PrintSummary(finalResult)
+// lookalikes returns an identity for every lookalike handle of a brand
+func lookalikes(brand string) []models.Identity {
+	var list []models.Identity
+	for _, c := range permute.Generate(brand, maxPerms) {
+		list = append(list, models.Identity{
+			Username:    c.Username,
+			Permutation: &models.Permutation{Of: brand, Transform: c.Transform, Detail: c.Detail},
+		})
 	}
+	return list
+}
 
-	if htmlReport != "" {
-		if err := reporter.ExportHTML(finalResult, htmlReport); err != nil {
-			return fmt.Errorf("failed to save HTML report: %w", err)
+// homoglyphVariants returns an identity for every homoglyph variant of a
+// brand that at least one platform allows as a handle
+func homoglyphVariants(brand string, list []plugins.Plugin) []models.Identity {
+	var ids []models.Identity
+	for _, v := range confusables.Variants(brand, maxPerms) {
+		if !slices.ContainsFunc(list, func(p plugins.Plugin) bool {
+			hv, ok := p.(plugins.HandleValidator)
+			return !ok || hv.ValidHandle(v.Text) == nil
+		}) {
+			continue
 		}
-		color.Cyan("📊 HTML report saved to: %s", htmlReport)
+		ids = append(ids, models.Identity{
+			Username:    v.Text,
+			Permutation: &models.Permutation{Of: brand, Transform: confusables.Transform, Detail: v.Detail},
+		})
 	}
+	return ids
+}
 
-	return nil
+// brand returns the name a target's accounts are expected to go by: the
+// handle itself, or the first label of a domain without "www."
+func brand(t models.Target) string {
+	if t.Type != models.TargetDomain {
+		return t.Value
+	}
+	host := t.Value
+	if u, err := url.Parse(t.Value); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	label, _, _ := strings.Cut(host, ".")
+	return label
+}
+
+// mergeNames returns the union of two name lists, ignoring case
+func mergeNames(a, b []string) []string {
+	for _, name := range b {
+		if !containsFold(a, name) {
+			a = append(a, name)
+		}
+	}
+	return a
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTargets collects targets from the arguments and the optional targets
+// file ("-" reads stdin)
+func loadTargets(args []string, file string) ([]models.Target, error) {
+	var list []models.Target
+	for _, a := range args {
+		list = append(list, targets.New(a, models.TargetAuto))
+	}
+
+	if file != "" {
+		var r io.Reader = os.Stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+		parsed, err := targets.Parse(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read targets: %w", err)
+		}
+		list = append(list, parsed...)
+	}
+
+	return targets.Dedupe(list), nil
+}
+
+// discoverIdentities resolves every target to the identities to check.
+// Username targets are their own identity; domain sites are crawled for
+// social links concurrently and every handle found becomes an identity with
+// the links that revealed it. A site that cannot be crawled is returned as a
+// failed execution at its index.
+func discoverIdentities(ctx context.Context, list []models.Target, workers int) ([][]models.Identity, []*models.Execution) {
+	identities := make([][]models.Identity, len(list))
+	failures := make([]*models.Execution, len(list))
+	extractor := scanner.NewExtractor(scanner.CrawlOptions{
+		MaxDepth:     crawlDepth,
+		MaxPages:     crawlPages,
+		IgnoreRobots: noRobots,
+		ExpandLinks:  expandLinks,
+	})
+
+	var domains []int
+	for i, t := range list {
+		if t.Type == models.TargetDomain {
+			domains = append(domains, i)
+		} else {
+			identities[i] = []models.Identity{{Username: t.Value}}
+		}
+	}
+	if len(domains) == 0 {
+		return identities, failures
+	}
+
+	if !jsonOutput {
+		color.Yellow("🔍 Extracting social links from %d domain(s)...", len(domains))
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				start := time.Now()
+				links, err := extractor.ExtractSocialLinks(ctx, list[i].Value)
+				if err != nil {
+					if !jsonOutput && verbose {
+						color.Red("   ❌ %s: %v", list[i].Value, err)
+					}
+					failures[i] = &models.Execution{
+						Plugin:     discoveryPlugin,
+						Target:     list[i].Value,
+						Duration:   time.Since(start),
+						Outcome:    models.OutcomeFailed,
+						ErrorClass: engine.Classify(err),
+						Message:    err.Error(),
+					}
+					continue
+				}
+				identities[i] = groupLinks(links)
+			}
+		}()
+	}
+	for _, i := range domains {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return identities, failures
+}
+
+// groupLinks turns extracted links into one identity per username, in the
+// order the usernames were first seen
+func groupLinks(links []scanner.Info) []models.Identity {
+	var identities []models.Identity
+	index := make(map[string]int)
+	for _, l := range links {
+		i, ok := index[l.Username]
+		if !ok {
+			i = len(identities)
+			index[l.Username] = i
+			identities = append(identities, models.Identity{Username: l.Username})
+		}
+		src := models.Source{Platform: l.Platform, URL: l.URL, Profile: l.Profile, Page: l.Page, Via: l.Via, Redirects: l.Redirects}
+		if !slices.ContainsFunc(identities[i].Sources, func(s models.Source) bool {
+			return s.Platform == src.Platform && s.URL == src.URL && s.Page == src.Page && s.Via == src.Via
+		}) {
+			identities[i].Sources = append(identities[i].Sources, src)
+		}
+	}
+	return identities
 }
@@ -0,0 +1,80 @@
+// Package activity flags abandoned accounts: official profiles nobody has
+// posted from in a long time, whose owners would not notice a takeover or
+// an impersonator answering in their name.
+package activity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// DefaultMonths is the inactivity after which an account is abandoned
+const DefaultMonths = 12
+
+// Policy decides when an account is stale
+type Policy struct {
+	MaxInactive time.Duration // inactivity after which an account is abandoned; 0 disables the policy
+	Now         time.Time     // reference time, the current time when zero
+}
+
+// NewPolicy returns a policy abandoning accounts inactive for more than the
+// given number of months; 0 disables it
+func NewPolicy(months int) Policy {
+	return Policy{MaxInactive: time.Duration(months) * 30 * 24 * time.Hour}
+}
+
+// Apply marks the existing accounts of an official identity as abandoned
+// when their last activity is older than the policy allows. An account
+// that never posted counts as inactive since it was created. Lookalike
+// identities are left alone: they belong to someone else. So are the
+// accounts of a discovered identity on platforms its sources do not link,
+// which merely share the handle.
+func (p Policy) Apply(id *models.Identity) {
+	if p.MaxInactive <= 0 || id.Permutation != nil {
+		return
+	}
+	now := p.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	for i := range id.Findings {
+		f := &id.Findings[i]
+		if f.Status != models.StatusExists || f.Profile == nil || !linked(id.Sources, f.PluginName) {
+			continue
+		}
+		since, why := lastActivity(f.Profile)
+		if since.IsZero() || now.Sub(since) <= p.MaxInactive {
+			continue
+		}
+
+		f.SetMeta("last_active", since)
+		f.SetMeta("inactive_days", int(now.Sub(since).Hours()/24))
+		f.Status = models.StatusAbandoned
+		f.Description = fmt.Sprintf("%s (abandoned: %s %s)", f.Description, why, since.Format("Jan 2006"))
+	}
+}
+
+// linked reports whether the sources link the identity on platform; an
+// identity without sources was given as the target itself
+func linked(sources []models.Source, platform string) bool {
+	return len(sources) == 0 || slices.ContainsFunc(sources, func(s models.Source) bool {
+		return strings.EqualFold(s.Platform, platform)
+	})
+}
+
+// lastActivity returns when the account was last seen active and how that
+// is known; zero when the profile tells nothing
+func lastActivity(p *models.Profile) (time.Time, string) {
+	switch {
+	case p.LastActive != nil:
+		return *p.LastActive, "last active"
+	case p.Posts != nil && *p.Posts == 0 && p.CreatedAt != nil:
+		return *p.CreatedAt, "no posts since joining"
+	}
+	return time.Time{}, ""
+}
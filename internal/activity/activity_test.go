@@ -0,0 +1,80 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestPolicy_Apply(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(year int, month time.Month) *time.Time {
+		t := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	count := func(n int64) *int64 { return &n }
+
+	tests := []struct {
+		name        string
+		status      models.Status
+		profile     *models.Profile
+		permutation *models.Permutation
+		months      int
+		want        models.Status
+	}{
+		{name: "Inactive for two years", status: models.StatusExists, profile: &models.Profile{LastActive: at(2024, 5)}, months: 12, want: models.StatusAbandoned},
+		{name: "Recently active", status: models.StatusExists, profile: &models.Profile{LastActive: at(2026, 3)}, months: 12, want: models.StatusExists},
+		{name: "Stricter policy", status: models.StatusExists, profile: &models.Profile{LastActive: at(2026, 3)}, months: 1, want: models.StatusAbandoned},
+		{name: "Never posted", status: models.StatusExists, profile: &models.Profile{Posts: count(0), CreatedAt: at(2020, 1)}, months: 12, want: models.StatusAbandoned},
+		{name: "Posts but no dates", status: models.StatusExists, profile: &models.Profile{Posts: count(40), CreatedAt: at(2020, 1)}, months: 12, want: models.StatusExists},
+		{name: "No profile", status: models.StatusExists, months: 12, want: models.StatusExists},
+		{name: "Private account", status: models.StatusPrivate, profile: &models.Profile{LastActive: at(2020, 1)}, months: 12, want: models.StatusPrivate},
+		{name: "Lookalike", status: models.StatusExists, profile: &models.Profile{LastActive: at(2020, 1)}, permutation: &models.Permutation{Of: "acme"}, months: 12, want: models.StatusExists},
+		{name: "Disabled", status: models.StatusExists, profile: &models.Profile{LastActive: at(2020, 1)}, want: models.StatusExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := models.Identity{
+				Username:    "acme",
+				Permutation: tt.permutation,
+				Findings:    []models.Finding{{PluginName: "Twitter", Status: tt.status, Profile: tt.profile, Description: "Twitter profile found"}},
+			}
+			p := NewPolicy(tt.months)
+			p.Now = now
+			p.Apply(&id)
+
+			f := id.Findings[0]
+			if f.Status != tt.want {
+				t.Fatalf("Apply() status = %s, want %s", f.Status, tt.want)
+			}
+			if f.Status == models.StatusAbandoned {
+				if _, ok := f.Metadata["inactive_days"].(int); !ok {
+					t.Errorf("Apply() metadata = %v, want inactive_days", f.Metadata)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicy_ApplyCrossPlatform(t *testing.T) {
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := models.Identity{
+		Username: "acme",
+		Sources:  []models.Source{{Platform: "Twitter", URL: "https://x.com/acme", Page: "https://acme.example/"}},
+		Findings: []models.Finding{
+			{PluginName: "Twitter", Status: models.StatusExists, Profile: &models.Profile{LastActive: &old}},
+			{PluginName: "GitHub", Status: models.StatusExists, Profile: &models.Profile{LastActive: &old}},
+		},
+	}
+
+	NewPolicy(12).Apply(&id)
+
+	if got := id.Findings[0].Status; got != models.StatusAbandoned {
+		t.Errorf("linked account status = %s, want %s", got, models.StatusAbandoned)
+	}
+	if got := id.Findings[1].Status; got != models.StatusExists {
+		t.Errorf("unlinked account status = %s, want %s: it only shares the handle", got, models.StatusExists)
+	}
+}
@@ -0,0 +1,160 @@
+// Package avatar spots impersonators reusing a brand's logo: it downloads
+// the avatars of the accounts found, computes their perceptual hashes in
+// pure Go and compares them with reference images of the brand.
+package avatar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// DefaultThreshold is the largest hash distance, out of 64 bits, at which an
+// avatar is considered a copy of a brand image
+const DefaultThreshold = 10
+
+// maxSize caps the avatar downloads
+const maxSize = 5 << 20
+
+// Reference is a brand image avatars are compared with
+type Reference struct {
+	Name   string
+	Hashes Hashes
+}
+
+// LoadReferences reads and hashes the brand images at the given paths
+func LoadReferences(paths ...string) ([]Reference, error) {
+	refs := make([]Reference, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		h, err := Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		refs = append(refs, Reference{Name: filepath.Base(path), Hashes: h})
+	}
+	return refs, nil
+}
+
+// Matcher downloads and hashes avatars, each URL once, and matches them
+// against the brand images
+type Matcher struct {
+	client    *httpx.Client
+	refs      []Reference
+	threshold int
+
+	mu    sync.Mutex
+	cache map[string]*fetch
+}
+
+type fetch struct {
+	done   chan struct{}
+	hashes Hashes
+	err    error
+}
+
+// NewMatcher creates a matcher downloading through client. Without
+// references avatars are only hashed.
+func NewMatcher(client *httpx.Client, refs []Reference, threshold int) *Matcher {
+	return &Matcher{client: client, refs: refs, threshold: threshold, cache: make(map[string]*fetch)}
+}
+
+// Annotate hashes the avatar of every account of an identity into its
+// profile. Lookalike findings, those naming the brand they imitate in
+// "impersonation_of", are also compared with the brand images: the closest
+// one is recorded in "avatar_reference" and "avatar_distance", and
+// "avatar_match" is set when it is within the threshold. Avatars that
+// cannot be downloaded or decoded are skipped.
+func (m *Matcher) Annotate(ctx context.Context, id *models.Identity) {
+	var wg sync.WaitGroup
+	for i := range id.Findings {
+		if p := id.Findings[i].Profile; p != nil && p.AvatarURL != "" {
+			wg.Add(1)
+			go func(u string) {
+				defer wg.Done()
+				m.hash(ctx, u)
+			}(p.AvatarURL)
+		}
+	}
+	wg.Wait()
+
+	for i := range id.Findings {
+		f := &id.Findings[i]
+		if f.Profile == nil || f.Profile.AvatarURL == "" {
+			continue
+		}
+		h, err := m.hash(ctx, f.Profile.AvatarURL)
+		if err != nil {
+			continue
+		}
+		prof := *f.Profile
+		prof.AvatarHash = h.String()
+		f.Profile = &prof
+
+		if _, ok := f.Metadata["impersonation_of"]; !ok || len(m.refs) == 0 {
+			continue
+		}
+		best := m.refs[0]
+		dist := h.Distance(best.Hashes)
+		for _, ref := range m.refs[1:] {
+			if d := h.Distance(ref.Hashes); d < dist {
+				best, dist = ref, d
+			}
+		}
+		f.SetMeta("avatar_reference", best.Name)
+		f.SetMeta("avatar_distance", dist)
+		f.SetMeta("avatar_match", dist <= m.threshold)
+		if dist <= m.threshold {
+			f.Description = fmt.Sprintf("%s (avatar copies brand image '%s')", f.Description, best.Name)
+		}
+	}
+}
+
+// hash downloads and hashes an avatar, once per URL
+func (m *Matcher) hash(ctx context.Context, u string) (Hashes, error) {
+	m.mu.Lock()
+	f, ok := m.cache[u]
+	if !ok {
+		f = &fetch{done: make(chan struct{})}
+		m.cache[u] = f
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		f.hashes, f.err = m.download(ctx, u)
+		close(f.done)
+	}
+	select {
+	case <-f.done:
+		return f.hashes, f.err
+	case <-ctx.Done():
+		return Hashes{}, ctx.Err()
+	}
+}
+
+func (m *Matcher) download(ctx context.Context, u string) (Hashes, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Hashes{}, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Hashes{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Hashes{}, resp.StatusError()
+	}
+	return Decode(io.LimitReader(resp.Body, maxSize))
+}
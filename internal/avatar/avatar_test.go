@@ -0,0 +1,113 @@
+package avatar
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestMatcher_Annotate(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/logo.png":
+			png.Encode(w, logo(96, color.White))
+		case "/cat.png":
+			png.Encode(w, stripes(96))
+		case "/broken.png":
+			w.Write([]byte("not an image"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "acme.png")
+	writePNG(t, path, logo(300, color.White))
+	refs, err := LoadReferences(path)
+	if err != nil {
+		t.Fatalf("LoadReferences() error = %v", err)
+	}
+
+	lookalike := func(avatar string) models.Finding {
+		return models.Finding{
+			PluginName: "Twitter",
+			Status:     models.StatusExists,
+			Metadata:   map[string]interface{}{"impersonation_of": "acme"},
+			Profile:    &models.Profile{AvatarURL: srv.URL + avatar},
+		}
+	}
+	id := models.Identity{
+		Username: "acme_hq",
+		Findings: []models.Finding{
+			lookalike("/logo.png"),
+			lookalike("/cat.png"),
+			lookalike("/broken.png"),
+			lookalike("/missing.png"),
+			{PluginName: "GitHub", Status: models.StatusExists, Profile: &models.Profile{AvatarURL: srv.URL + "/logo.png"}},
+		},
+	}
+
+	NewMatcher(httpx.NewClient(0, nil, httpx.RetryPolicy{}), refs, DefaultThreshold).Annotate(context.Background(), &id)
+
+	copied := id.Findings[0]
+	if copied.Metadata["avatar_match"] != true || copied.Metadata["avatar_reference"] != "acme.png" {
+		t.Errorf("logo avatar metadata = %v, want a match with acme.png", copied.Metadata)
+	}
+	if !strings.Contains(copied.Description, "avatar copies brand image 'acme.png'") {
+		t.Errorf("logo avatar description = %q", copied.Description)
+	}
+	if copied.Profile.AvatarHash == "" {
+		t.Error("logo avatar was not hashed into the profile")
+	}
+	if id.Findings[1].Metadata["avatar_match"] != false {
+		t.Errorf("unrelated avatar metadata = %v, want no match", id.Findings[1].Metadata)
+	}
+	for _, f := range id.Findings[2:4] {
+		if _, ok := f.Metadata["avatar_match"]; ok || f.Profile.AvatarHash != "" {
+			t.Errorf("unusable avatar %s was annotated: %v", f.Profile.AvatarURL, f.Metadata)
+		}
+	}
+	official := id.Findings[4]
+	if _, ok := official.Metadata["avatar_match"]; ok || official.Profile.AvatarHash != copied.Profile.AvatarHash {
+		t.Errorf("official account = %+v, want only its avatar hashed", official)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("server got %d requests, want every avatar downloaded once", got)
+	}
+}
+
+func TestLoadReferences_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logo.png")
+	os.WriteFile(path, []byte("not an image"), 0o644)
+	if _, err := LoadReferences(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadReferences() error = %v, want one naming the file", err)
+	}
+	if _, err := LoadReferences(filepath.Join(t.TempDir(), "missing.png")); err == nil {
+		t.Error("LoadReferences() accepted a missing file")
+	}
+}
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,231 @@
+package avatar
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders for the formats platforms serve avatars in
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+)
+
+// Hash is a 64-bit perceptual hash: similar images differ in few bits
+type Hash uint64
+
+// Distance returns the number of bits two hashes differ in, from 0
+// (identical) to 64
+func Distance(a, b Hash) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
+
+// Hashes holds the three perceptual hashes of an image
+type Hashes struct {
+	Average    Hash // aHash: pixels brighter than the mean
+	Difference Hash // dHash: brightness gradients between neighbors
+	Perceptual Hash // pHash: low DCT frequencies above their median
+}
+
+// MaxDimension bounds the width and height of the images Decode accepts; a
+// few kilobytes of PNG or GIF can claim dimensions that decode into
+// gigabytes
+const MaxDimension = 4096
+
+// thumbSize is the side of the gray thumbnail the three hashes are computed
+// from, so a large image is only read once
+const thumbSize = 64
+
+// Compute hashes an image
+func Compute(img image.Image) Hashes {
+	thumb := shrink(img)
+	return Hashes{Average: Average(thumb), Difference: Difference(thumb), Perceptual: Perceptual(thumb)}
+}
+
+// Decode reads a PNG, JPEG or GIF image and hashes it. Images larger than
+// MaxDimension on either side are rejected before they are decoded.
+func Decode(r io.Reader) (Hashes, error) {
+	var head bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return Hashes{}, err
+	}
+	if cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+		return Hashes{}, fmt.Errorf("image of %dx%d pixels exceeds %dx%d", cfg.Width, cfg.Height, MaxDimension, MaxDimension)
+	}
+	img, _, err := image.Decode(io.MultiReader(&head, r))
+	if err != nil {
+		return Hashes{}, err
+	}
+	return Compute(img), nil
+}
+
+// Distance returns the median of the distances of the three hashes, so a
+// single hash thrown off by a recolored or cropped copy does not decide
+func (h Hashes) Distance(o Hashes) int {
+	d := []int{
+		Distance(h.Average, o.Average),
+		Distance(h.Difference, o.Difference),
+		Distance(h.Perceptual, o.Perceptual),
+	}
+	slices.Sort(d)
+	return d[1]
+}
+
+// String formats the hashes as 48 hex digits: aHash, dHash, pHash
+func (h Hashes) String() string {
+	return fmt.Sprintf("%016x%016x%016x", uint64(h.Average), uint64(h.Difference), uint64(h.Perceptual))
+}
+
+// ParseHashes reads hashes formatted by Hashes.String
+func ParseHashes(s string) (Hashes, error) {
+	if len(s) != 48 {
+		return Hashes{}, fmt.Errorf("invalid avatar hash %q", s)
+	}
+	var out [3]Hash
+	for i := range out {
+		v, err := strconv.ParseUint(s[i*16:(i+1)*16], 16, 64)
+		if err != nil {
+			return Hashes{}, fmt.Errorf("invalid avatar hash %q", s)
+		}
+		out[i] = Hash(v)
+	}
+	return Hashes{Average: out[0], Difference: out[1], Perceptual: out[2]}, nil
+}
+
+// Average computes the aHash of an image: shrunk to 8x8 gray pixels, one
+// bit per pixel brighter than the mean
+func Average(img image.Image) Hash {
+	px := gray(img, 8, 8)
+	mean := 0.0
+	for _, v := range px {
+		mean += v
+	}
+	mean /= float64(len(px))
+
+	var h Hash
+	for i, v := range px {
+		if v > mean {
+			h |= 1 << i
+		}
+	}
+	return h
+}
+
+// Difference computes the dHash of an image: shrunk to 9x8 gray pixels, one
+// bit per pixel brighter than its right neighbor
+func Difference(img image.Image) Hash {
+	px := gray(img, 9, 8)
+	var h Hash
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if px[y*9+x] > px[y*9+x+1] {
+				h |= 1 << (y*8 + x)
+			}
+		}
+	}
+	return h
+}
+
+// dctSize is the side of the image the pHash transforms
+const dctSize = 32
+
+// dctCos holds cos((2x+1)uπ/2N) for the 8 lowest frequencies u
+var dctCos = func() [8][dctSize]float64 {
+	var c [8][dctSize]float64
+	for u := range c {
+		for x := range c[u] {
+			c[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * dctSize))
+		}
+	}
+	return c
+}()
+
+// Perceptual computes the pHash of an image: shrunk to 32x32 gray pixels
+// and transformed with a DCT, one bit per coefficient of the 8x8 lowest
+// frequencies above their median. The DC term, the overall brightness, is
+// left out of the median.
+func Perceptual(img image.Image) Hash {
+	px := gray(img, dctSize, dctSize)
+
+	var coeffs [64]float64
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			sum := 0.0
+			for y := 0; y < dctSize; y++ {
+				for x := 0; x < dctSize; x++ {
+					sum += px[y*dctSize+x] * dctCos[u][x] * dctCos[v][y]
+				}
+			}
+			coeffs[v*8+u] = sum
+		}
+	}
+
+	sorted := slices.Clone(coeffs[1:])
+	slices.Sort(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var h Hash
+	for i, c := range coeffs {
+		if c > median {
+			h |= 1 << i
+		}
+	}
+	return h
+}
+
+// gray shrinks an image to w x h by averaging the luma of the pixels each
+// cell covers. Transparent pixels are laid over white, as platforms show
+// them.
+func gray(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	out := make([]float64, w*h)
+	if b.Empty() {
+		return out
+	}
+	for cy := 0; cy < h; cy++ {
+		y0, y1 := span(b.Min.Y, b.Dy(), cy, h)
+		for cx := 0; cx < w; cx++ {
+			x0, x1 := span(b.Min.X, b.Dx(), cx, w)
+			sum := 0.0
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					r, g, bl, a := img.At(x, y).RGBA()
+					white := float64(0xffff - a)
+					sum += 0.299*(float64(r)+white) + 0.587*(float64(g)+white) + 0.114*(float64(bl)+white)
+				}
+			}
+			out[cy*w+cx] = sum / float64((y1-y0)*(x1-x0)) / 0xffff * 255
+		}
+	}
+	return out
+}
+
+// shrink reduces an image larger than thumbSize to a gray thumbnail of that
+// size, laid over white like gray does
+func shrink(img image.Image) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= thumbSize && b.Dy() <= thumbSize {
+		return img
+	}
+	thumb := image.NewGray16(image.Rect(0, 0, thumbSize, thumbSize))
+	for i, v := range gray(img, thumbSize, thumbSize) {
+		thumb.SetGray16(i%thumbSize, i/thumbSize, color.Gray16{Y: uint16(math.Round(v / 255 * 0xffff))})
+	}
+	return thumb
+}
+
+// span returns the source pixels [lo, hi) covered by cell i of n along an
+// axis of the given origin and length, at least one pixel wide
+func span(origin, length, i, n int) (int, int) {
+	lo := origin + i*length/n
+	hi := origin + (i+1)*length/n
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
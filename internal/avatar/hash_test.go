@@ -0,0 +1,108 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// logo draws a dark ring with a bar across on a light background at the
+// given size, the way a brand mark scales
+func logo(size int, bg color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	c := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := (float64(x)-c)/c, (float64(y)-c)/c
+			d := dx*dx + dy*dy
+			switch {
+			case d > 0.4 && d < 0.7, dy > -0.15 && dy < 0.15 && dx > -0.6 && dx < 0.2:
+				img.Set(x, y, color.NRGBA{R: 20, G: 40, B: 120, A: 255})
+			default:
+				img.Set(x, y, bg)
+			}
+		}
+	}
+	return img
+}
+
+// stripes draws an unrelated picture
+func stripes(size int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			v := uint8(255 * ((x + 2*y) / (size / 6) % 2))
+			img.Set(x, y, color.NRGBA{R: v, G: v / 2, B: 255 - v, A: 255})
+		}
+	}
+	return img
+}
+
+func TestHashes_Distance(t *testing.T) {
+	brand := Compute(logo(256, color.White))
+
+	tests := []struct {
+		name  string
+		img   image.Image
+		match bool
+	}{
+		{name: "Same logo", img: logo(256, color.White), match: true},
+		{name: "Downscaled", img: logo(48, color.White), match: true},
+		{name: "Off-white background", img: logo(200, color.NRGBA{R: 235, G: 235, B: 230, A: 255}), match: true},
+		{name: "Transparent background", img: logo(128, color.Transparent), match: true},
+		{name: "Unrelated picture", img: stripes(256)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := brand.Distance(Compute(tt.img))
+			if (d <= DefaultThreshold) != tt.match {
+				t.Errorf("Distance() = %d, want match %v", d, tt.match)
+			}
+		})
+	}
+}
+
+func TestParseHashes(t *testing.T) {
+	h := Compute(logo(64, color.White))
+	got, err := ParseHashes(h.String())
+	if err != nil || got != h {
+		t.Errorf("ParseHashes(%q) = %v, %v, want %v", h.String(), got, err, h)
+	}
+	for _, s := range []string{"", "abc", h.String()[:47] + "z"} {
+		if _, err := ParseHashes(s); err == nil {
+			t.Errorf("ParseHashes(%q) accepted an invalid hash", s)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, logo(256, color.White)); err != nil {
+		t.Fatal(err)
+	}
+	h, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if d := h.Distance(Compute(logo(256, color.White))); d != 0 {
+		t.Errorf("Decode() hashes differ from Compute() by %d", d)
+	}
+
+	// A tiny GIF whose header claims 5000x5000 pixels
+	buf.Reset()
+	if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.White}), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint16(data[6:], 5000)
+	binary.LittleEndian.PutUint16(data[8:], 5000)
+	if _, err := Decode(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "5000x5000") {
+		t.Errorf("Decode() error = %v, want the oversized image rejected", err)
+	}
+}
@@ -0,0 +1,213 @@
+// Package confusables detects handles that imitate a brand with homoglyphs:
+// characters from other scripts, such as Cyrillic 'а' or Greek 'ο', that
+// render like the Latin letters of the brand. It is based on a subset of the
+// Unicode confusables data (UTS #39), embedded in confusables.txt.
+package confusables
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Indicator identifies findings about homoglyph lookalikes of a handle
+const Indicator = "homoglyph_impersonation"
+
+// Transform names the homoglyph substitution in models.Permutation
+const Transform = "homoglyph"
+
+//go:embed confusables.txt
+var data string
+
+// glyph is a character that can be confused with a Latin letter
+type glyph struct {
+	r    rune
+	name string
+}
+
+var (
+	// prototypes maps confusable characters to the Latin letter they pass for
+	prototypes = make(map[rune]rune)
+	// homoglyphs lists the non-ASCII characters that pass for each letter,
+	// in file order, leaving out compatibility forms such as fullwidth and
+	// mathematical letters that handles cannot hold
+	homoglyphs = make(map[rune][]glyph)
+)
+
+func init() {
+	if err := load(data); err != nil {
+		panic("confusables: " + err.Error())
+	}
+}
+
+// load parses confusables data: "source ; prototype ; type # comment" lines
+// of hexadecimal code points
+func load(src string) error {
+	s := bufio.NewScanner(strings.NewReader(src))
+	for n := 1; s.Scan(); n++ {
+		line, comment, _ := strings.Cut(s.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			return fmt.Errorf("line %d: expected source and prototype", n)
+		}
+		from, err := codePoint(fields[0])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		to, err := codePoint(fields[1])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		prototypes[from] = to
+		if from > unicode.MaxASCII && norm.NFKC.String(string(from)) == string(from) {
+			homoglyphs[to] = append(homoglyphs[to], glyph{r: from, name: glyphName(comment)})
+		}
+	}
+	return s.Err()
+}
+
+func codePoint(field string) (rune, error) {
+	v, err := strconv.ParseUint(strings.TrimSpace(field), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid code point %q", strings.TrimSpace(field))
+	}
+	return rune(v), nil
+}
+
+// glyphName returns the character name from a "( а → a ) NAME → NAME" comment
+func glyphName(comment string) string {
+	_, rest, ok := strings.Cut(comment, ")")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, "→")
+	return strings.TrimSpace(name)
+}
+
+// Skeleton returns the form two strings share when they look alike, as UTS
+// #39 defines it after case folding: the string is decomposed to NFD, every
+// confusable character is replaced by the Latin letter it passes for, and the
+// result is decomposed again
+func Skeleton(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if p, ok := prototypes[r]; ok {
+			r = p
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFD.String(b.String())
+}
+
+// Confusable reports whether name imitates handle: they differ but share a
+// skeleton
+func Confusable(handle, name string) bool {
+	return !strings.EqualFold(handle, name) && Skeleton(handle) == Skeleton(name)
+}
+
+// Variant is a homoglyph lookalike of a handle
+type Variant struct {
+	Text   string
+	Detail string // e.g. "'a' as 'а' (CYRILLIC SMALL LETTER A)"
+}
+
+// Variants returns up to max homoglyph lookalikes of handle, 0 meaning no
+// limit: first one per script with every letter the script can imitate
+// replaced, which is how whole handles are spoofed, then one for every
+// single-character substitution
+func Variants(handle string, max int) []Variant {
+	src := []rune(strings.ToLower(handle))
+	var list []Variant
+	seen := map[string]bool{string(src): true}
+	add := func(text, detail string) bool {
+		if !seen[text] {
+			seen[text] = true
+			list = append(list, Variant{Text: text, Detail: detail})
+		}
+		return max > 0 && len(list) >= max
+	}
+
+	for _, script := range []struct {
+		name  string
+		table *unicode.RangeTable
+	}{{"Cyrillic", unicode.Cyrillic}, {"Greek", unicode.Greek}} {
+		v := make([]rune, len(src))
+		replaced := 0
+		for i, c := range src {
+			v[i] = c
+			for _, g := range homoglyphs[c] {
+				if unicode.Is(script.table, g.r) {
+					v[i] = g.r
+					replaced++
+					break
+				}
+			}
+		}
+		if replaced > 1 && add(string(v), fmt.Sprintf("%d letters as %s", replaced, script.name)) {
+			return list
+		}
+	}
+
+	for i, c := range src {
+		for _, g := range homoglyphs[c] {
+			v := make([]rune, len(src))
+			copy(v, src)
+			v[i] = g.r
+			if add(string(v), fmt.Sprintf("'%c' as '%c' (%s)", c, g.r, g.name)) {
+				return list
+			}
+		}
+	}
+	return list
+}
+
+// Detect returns a finding for every account of the identity that exists
+// under a handle confusable with brand, such as a site linking to "g00gle"
+// for "google". Identities generated as lookalikes are already reported
+// through their own findings.
+func Detect(brand string, id models.Identity) []models.Finding {
+	if id.Permutation != nil {
+		return nil
+	}
+	if !Confusable(brand, id.Username) {
+		return nil
+	}
+
+	var findings []models.Finding
+	for _, f := range id.Findings {
+		switch f.Status {
+		case models.StatusExists, models.StatusPrivate, models.StatusAbandoned:
+		default:
+			continue
+		}
+		meta := map[string]interface{}{
+			"impersonation_of":  brand,
+			"skeleton":          Skeleton(id.Username),
+			"profile_indicator": f.Indicator,
+		}
+		if v, ok := f.Metadata["url"]; ok {
+			meta["url"] = v
+		}
+		findings = append(findings, models.Finding{
+			PluginName:  f.PluginName,
+			Indicator:   Indicator,
+			Value:       id.Username,
+			Status:      f.Status,
+			Description: fmt.Sprintf("%s account '%s' looks identical to '%s'", f.PluginName, id.Username, brand),
+			Metadata:    meta,
+			Profile:     f.Profile,
+			Timestamp:   time.Now(),
+		})
+	}
+	return findings
+}
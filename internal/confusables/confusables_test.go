@@ -0,0 +1,136 @@
+package confusables
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		confusable bool
+	}{
+		{a: "acme", b: "асmе", confusable: true},     // Cyrillic а, с, е
+		{a: "paypal", b: "раураl", confusable: true}, // Cyrillic р, а, у
+		{a: "google", b: "g00gle", confusable: true},
+		{a: "google", b: "gοοgle", confusable: true},   // Greek omicron
+		{a: "Acme", b: "ＡＣＭＥ", confusable: true},       // fullwidth
+		{a: "acme", b: "𝐚𝐜𝐦𝐞", confusable: true},       // mathematical bold
+		{a: "Acme", b: "𝔸𝕔𝕞𝕖", confusable: true},       // double-struck capital
+		{a: "acmé", b: "acme\u0301", confusable: true}, // precomposed and combining accent
+		{a: "acme", b: "ACME"},                         // same handle
+		{a: "acme", b: "асме"},                         // Cyrillic м does not pass for m
+		{a: "acme", b: "acne"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.b, func(t *testing.T) {
+			if got := Confusable(tt.a, tt.b); got != tt.confusable {
+				t.Errorf("Confusable(%q, %q) = %v, want %v (skeletons %q, %q)", tt.a, tt.b, got, tt.confusable, Skeleton(tt.a), Skeleton(tt.b))
+			}
+		})
+	}
+}
+
+func TestVariants(t *testing.T) {
+	list := Variants("Acme", 0)
+	if len(list) == 0 {
+		t.Fatal("Variants() returned nothing")
+	}
+	if list[0].Text != "асmе" || !strings.Contains(list[0].Detail, "Cyrillic") {
+		t.Errorf("first variant = %+v, want the whole handle in Cyrillic", list[0])
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range list {
+		if seen[v.Text] {
+			t.Errorf("Variants() returned %q twice", v.Text)
+		}
+		seen[v.Text] = true
+		if v.Text == "acme" {
+			t.Error("Variants() returned the handle itself")
+		}
+		if norm.NFKC.String(v.Text) != v.Text {
+			t.Errorf("variant %q (%s) uses a compatibility form no handle can hold", v.Text, v.Detail)
+		}
+		if !Confusable("acme", v.Text) {
+			t.Errorf("variant %q (%s) does not share the handle's skeleton", v.Text, v.Detail)
+		}
+	}
+	if !seen["acmе"] {
+		t.Error("Variants() is missing the single substitution of Cyrillic 'е'")
+	}
+
+	if got := Variants("acme", 3); len(got) != 3 {
+		t.Errorf("Variants(max 3) returned %d variants", len(got))
+	}
+}
+
+func TestDetect(t *testing.T) {
+	exists := models.Finding{
+		PluginName: "Twitter",
+		Indicator:  "twitter_profile",
+		Status:     models.StatusExists,
+		Metadata:   map[string]interface{}{"url": "https://twitter.com/g00gle"},
+	}
+
+	tests := []struct {
+		name string
+		id   models.Identity
+		want int
+	}{
+		{
+			name: "Confusable account exists",
+			id:   models.Identity{Username: "g00gle", Findings: []models.Finding{exists, {PluginName: "GitHub", Status: models.StatusAvailable}}},
+			want: 1,
+		},
+		{
+			name: "Confusable account abandoned",
+			id:   models.Identity{Username: "g00gle", Findings: []models.Finding{{PluginName: "Twitter", Indicator: "twitter_profile", Status: models.StatusAbandoned}}},
+			want: 1,
+		},
+		{
+			name: "The brand itself",
+			id:   models.Identity{Username: "google", Findings: []models.Finding{exists}},
+		},
+		{
+			name: "Homoglyph variant reported by its own findings",
+			id: models.Identity{
+				Username:    "gооgle",
+				Permutation: &models.Permutation{Of: "google", Transform: Transform},
+				Findings:    []models.Finding{exists},
+			},
+		},
+		{
+			name: "Digit substitution variant reported by its own findings",
+			id: models.Identity{
+				Username:    "g00gle",
+				Permutation: &models.Permutation{Of: "google", Transform: "digit_substitution"},
+				Findings:    []models.Finding{exists},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect("google", tt.id)
+			if len(got) != tt.want {
+				t.Fatalf("Detect() returned %d findings, want %d", len(got), tt.want)
+			}
+			for _, f := range got {
+				if f.Indicator != Indicator || f.Metadata["skeleton"] != "google" || f.Metadata["profile_indicator"] != "twitter_profile" {
+					t.Errorf("Detect() finding = %+v", f)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	if err := load("0430 ; zz ; MA\n"); err == nil {
+		t.Error("load() accepted an invalid code point")
+	}
+}
@@ -2,103 +2,325 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
 	"github.com/ismailtsdln/socialrecon/internal/models"
 	"github.com/ismailtsdln/socialrecon/internal/plugins"
+	"github.com/ismailtsdln/socialrecon/internal/ratelimit"
 )
 
 // Engine orchestrates the scanning process
 type Engine struct {
 	config  models.Config
 	plugins []plugins.Plugin
+	limiter *ratelimit.Limiter
+	client  *httpx.Client
+	// Worker pool shared by every run, so concurrent targets compete for the
+	// same MaxConcurrency slots
+	semaphore chan struct{}
+
+	obsMu     sync.Mutex // guards observers
+	observers []Observer
+	notifyMu  sync.Mutex // serializes calls to the subscribed observers
 }
 
-// NewEngine creates a new scanning engine
+// NewEngine creates a new scanning engine. HTTP plugins are switched to a
+// shared client so the per-host rate limits and retry policy hold across
+// every target scanned by this engine.
 func NewEngine(cfg models.Config, enabledPlugins []plugins.Plugin) *Engine {
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 10 * time.Second
+	}
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 1
+	}
+
+	retry := httpx.DefaultRetryPolicy()
+	if cfg.MaxAttempts > 0 {
+		retry.MaxAttempts = cfg.MaxAttempts
+	}
+	if cfg.RetryBaseDelay > 0 {
+		retry.BaseDelay = cfg.RetryBaseDelay
+	}
+	if cfg.RetryMaxDelay > 0 {
+		retry.MaxDelay = cfg.RetryMaxDelay
+	}
+
+	limiter := ratelimit.NewLimiter(cfg.RateLimit, cfg.RateBurst)
+	client := httpx.NewClient(cfg.RequestTimeout, limiter, retry)
+
+	overrides := make(map[string]float64, len(cfg.PluginRates))
+	for name, rate := range cfg.PluginRates {
+		overrides[strings.ToLower(name)] = rate
+	}
+
+	for _, p := range enabledPlugins {
+		hp, ok := p.(plugins.HTTPPlugin)
+		if !ok {
+			continue
+		}
+		hp.SetClient(client)
+		if rate, ok := overrides[strings.ToLower(p.Name())]; ok {
+			for _, host := range hp.Hosts() {
+				limiter.SetRate(host, rate)
+			}
+		}
+	}
+
 	return &Engine{
-		config:  cfg,
-		plugins: enabledPlugins,
+		config:    cfg,
+		plugins:   enabledPlugins,
+		limiter:   limiter,
+		client:    client,
+		semaphore: make(chan struct{}, cfg.MaxConcurrency),
 	}
 }
 
-// Run executes the scan across all configured plugins
+// Client returns the HTTP client shared by the engine's plugins, for
+// follow-up requests that must respect the same rate limits
+func (e *Engine) Client() *httpx.Client {
+	return e.client
+}
+
+// Run executes the scan across all configured plugins. The returned result
+// always carries the findings of the plugins that succeeded and one execution
+// record per plugin; if any plugin failed the error is a *RunError.
 func (e *Engine) Run(ctx context.Context, target string) (*models.ScanResult, error) {
+	return e.run(ctx, Job{Target: target}, nil)
+}
+
+// Job is a single target to scan, optionally restricted to some plugins
+type Job struct {
+	Target  string
+	Plugins []string // names of the plugins to run, case-insensitive; empty runs all
+}
+
+// Plugins returns the configured plugins with the given names, in
+// configuration order. Without names every plugin is returned.
+func (e *Engine) Plugins(names ...string) []plugins.Plugin {
+	if len(names) == 0 {
+		return e.plugins
+	}
+	var selected []plugins.Plugin
+	for _, p := range e.plugins {
+		for _, name := range names {
+			if strings.EqualFold(p.Name(), name) {
+				selected = append(selected, p)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// run executes a scan, reporting progress to the subscribed observers and
+// the optional per-run observer
+func (e *Engine) run(ctx context.Context, job Job, obs Observer) (*models.ScanResult, error) {
+	target := job.Target
+	selected := e.Plugins(job.Plugins...)
+	if obs != nil {
+		obs = &serialized{o: obs}
+	}
+
+	if e.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.Timeout)
+		defer cancel()
+	}
+
 	result := &models.ScanResult{
 		Target:    target,
 		Findings:  []models.Finding{},
 		StartTime: time.Now(),
 	}
 
-	findingChan := make(chan models.Finding)
-	errorChan := make(chan error)
-	var wg sync.WaitGroup
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed []*PluginError
+	)
 
-	// Worker pool pattern for plugins
-	semaphore := make(chan struct{}, e.config.MaxConcurrency)
+	e.emit(obs, Event{Type: EventScanStarted, Target: target, Total: len(selected)})
 
-	for _, p := range e.plugins {
+	for _, p := range selected {
 		wg.Add(1)
 		go func(pl plugins.Plugin) {
 			defer wg.Done()
 
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+			e.semaphore <- struct{}{}
+			e.emit(obs, Event{Type: EventPluginStarted, Target: target, Plugin: pl.Name()})
+			findings, exec, err := e.execute(ctx, pl, target)
+			// Free the slot before reporting, so a slow observer does not
+			// hold up the checks of other runs
+			<-e.semaphore
 
-			findings, err := pl.Check(ctx, target)
+			for i := range findings {
+				e.emit(obs, Event{Type: EventFinding, Target: target, Plugin: pl.Name(), Finding: &findings[i]})
+			}
 			if err != nil {
-				errorChan <- err
-				return
+				e.emit(obs, Event{Type: EventError, Target: target, Plugin: pl.Name(), Err: err})
 			}
-			for _, f := range findings {
-				findingChan <- f
+			e.emit(obs, Event{Type: EventPluginFinished, Target: target, Plugin: pl.Name(), Execution: &exec})
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Findings = append(result.Findings, findings...)
+			result.Executions = append(result.Executions, exec)
+			if err != nil {
+				failed = append(failed, err)
 			}
 		}(p)
 	}
 
-	// Closer goroutine
-	go func() {
-		wg.Wait()
-		close(findingChan)
-		close(errorChan)
-	}()
-
-	// Collect findings
-	var errors []error
-CollectLoop:
-	for {
-		select {
-		case f, ok := <-findingChan:
-			if !ok {
-				findingChan = nil
-			} else {
-				result.Findings = append(result.Findings, f)
-			}
-		case err, ok := <-errorChan:
-			if !ok {
-				errorChan = nil
-			} else if err != nil {
-				errors = append(errors, err)
+	wg.Wait()
+	result.EndTime = time.Now()
+
+	// Keep findings and records in plugin order regardless of completion order
+	order := make(map[string]int, len(e.plugins))
+	for i, p := range e.plugins {
+		order[p.Name()] = i
+	}
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		return order[result.Findings[i].PluginName] < order[result.Findings[j].PluginName]
+	})
+	sort.SliceStable(result.Executions, func(i, j int) bool {
+		return order[result.Executions[i].Plugin] < order[result.Executions[j].Plugin]
+	})
+	sort.SliceStable(failed, func(i, j int) bool {
+		return order[failed[i].Plugin] < order[failed[j].Plugin]
+	})
+
+	var err error
+	if len(failed) > 0 {
+		err = &RunError{Errors: failed}
+	}
+	e.emit(obs, Event{Type: EventScanFinished, Target: target, Result: result, Err: err})
+
+	return result, err
+}
+
+// RunBatch scans many targets through the engine's shared worker pool and
+// rate limiter. Results are returned in input order; if any check failed the
+// error is a single *RunError covering every target.
+func (e *Engine) RunBatch(ctx context.Context, targets []string) ([]*models.ScanResult, error) {
+	jobs := make([]Job, len(targets))
+	for i, t := range targets {
+		jobs[i] = Job{Target: t}
+	}
+	return e.RunJobs(ctx, jobs)
+}
+
+// RunJobs is RunBatch for jobs that each run their own set of plugins
+func (e *Engine) RunJobs(ctx context.Context, jobs []Job) ([]*models.ScanResult, error) {
+	results := make([]*models.ScanResult, len(jobs))
+	failures := make([][]*PluginError, len(jobs))
+	var wg sync.WaitGroup
+
+	// Bound the targets in flight; plugin checks are bounded by e.semaphore
+	queue := make(chan int)
+	for w := 0; w < e.config.MaxConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				res, err := e.run(ctx, jobs[i], nil)
+				results[i] = res
+
+				var runErr *RunError
+				if errors.As(err, &runErr) {
+					failures[i] = runErr.Errors
+				}
 			}
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		}
+		}()
+	}
 
-		if findingChan == nil && errorChan == nil {
-			break CollectLoop
-		}
+	for i := range jobs {
+		queue <- i
 	}
+	close(queue)
+	wg.Wait()
 
-	result.EndTime = time.Now()
+	var failed []*PluginError
+	for _, f := range failures {
+		failed = append(failed, f...)
+	}
+	if len(failed) > 0 {
+		return results, &RunError{Errors: failed}
+	}
+	return results, nil
+}
+
+// execute runs a single plugin and records how it went
+func (e *Engine) execute(ctx context.Context, pl plugins.Plugin, target string) ([]models.Finding, models.Execution, *PluginError) {
+	start := time.Now()
+	findings, err := pl.Check(ctx, target)
+
+	exec := models.Execution{
+		Plugin:   pl.Name(),
+		Target:   target,
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		pe := &PluginError{Plugin: pl.Name(), Target: target, Class: Classify(err), Err: err}
+		exec.Outcome = models.OutcomeFailed
+		exec.ErrorClass = pe.Class
+		exec.Message = err.Error()
+		exec.Attempts = httpx.Attempts(err)
+		return []models.Finding{failureFinding(pl, target, pe, exec.Attempts)}, exec, pe
+	}
 
-	if len(errors) > 0 {
-		// Return the first error for now, or we could wrap them
-		// For a scanner, returning results + errors might be better, but we follow the signature
-		if len(result.Findings) == 0 {
-			return result, errors[0]
+	exec.Outcome = models.OutcomeSkipped
+	for _, f := range findings {
+		if n, ok := f.Metadata["attempts"].(int); ok && n > exec.Attempts {
+			exec.Attempts = n
 		}
+		if f.Status == models.StatusInvalidUsername {
+			continue
+		}
+		if !f.Status.Conclusive() {
+			if exec.Outcome == models.OutcomeSkipped {
+				exec.Outcome = models.OutcomeInconclusive
+				exec.Message = f.Description
+			}
+			continue
+		}
+		exec.Outcome = models.OutcomeSuccess
+		exec.Message = ""
+	}
+
+	return findings, exec, nil
+}
+
+// failureFinding reports a failed check as its own finding so it is not lost
+// among the conclusive ones
+func failureFinding(pl plugins.Plugin, target string, pe *PluginError, attempts int) models.Finding {
+	f := models.Finding{
+		PluginName: pl.Name(),
+		Indicator:  "check_failure",
+		Value:      target,
+		Status:     models.StatusError,
+		Severity:   models.SeverityInfo,
+		Metadata: map[string]interface{}{
+			"error_class": pe.Class,
+			"error":       pe.Err.Error(),
+			"attempts":    attempts,
+		},
+		Timestamp: time.Now(),
+	}
+
+	if pe.Class == models.ErrorClassRateLimited {
+		f.Status = models.StatusRateLimited
+		f.Description = fmt.Sprintf("%s check for '%s' was rate limited by the platform", pl.Name(), target)
+	} else {
+		f.Description = fmt.Sprintf("%s check for '%s' failed (%s)", pl.Name(), target, pe.Class)
 	}
 
-	return result, nil
+	return f
 }
@@ -0,0 +1,250 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/plugins"
+)
+
+type fakePlugin struct {
+	name     string
+	findings []models.Finding
+	err      error
+}
+
+func (p *fakePlugin) Name() string        { return p.name }
+func (p *fakePlugin) Description() string { return "fake" }
+
+func (p *fakePlugin) Check(ctx context.Context, target string) ([]models.Finding, error) {
+	return p.findings, p.err
+}
+
+func TestEngine_RunPartialResults(t *testing.T) {
+	eng := NewEngine(models.Config{MaxConcurrency: 2}, fakes{
+		&fakePlugin{name: "A", findings: []models.Finding{{PluginName: "A", Status: "exists"}}},
+		&fakePlugin{name: "B", err: fmt.Errorf("boom: %w", context.DeadlineExceeded)},
+		&fakePlugin{name: "C", findings: []models.Finding{{PluginName: "C", Status: "unknown"}}},
+		&fakePlugin{name: "D"},
+	}.list())
+
+	result, err := eng.Run(context.Background(), "acme")
+	if result == nil {
+		t.Fatal("Run() returned no result")
+	}
+	statuses := make(map[string]models.Status)
+	for _, f := range result.Findings {
+		statuses[f.PluginName] = f.Status
+	}
+	wantStatuses := map[string]models.Status{
+		"A": models.StatusExists,
+		"B": models.StatusError,
+		"C": models.StatusUnknown,
+	}
+	if len(statuses) != len(wantStatuses) {
+		t.Errorf("Run() returned findings for %v, want %v", statuses, wantStatuses)
+	}
+	for plugin, want := range wantStatuses {
+		if statuses[plugin] != want {
+			t.Errorf("finding for %s status = %q, want %q", plugin, statuses[plugin], want)
+		}
+	}
+
+	var runErr *RunError
+	if !errors.As(err, &runErr) {
+		t.Fatalf("Run() error = %v, want *RunError", err)
+	}
+	if len(runErr.Errors) != 1 || runErr.Errors[0].Plugin != "B" {
+		t.Errorf("RunError.Errors = %v, want a single failure of B", runErr.Errors)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("errors.Is(err, DeadlineExceeded) = false")
+	}
+
+	want := map[string]models.Outcome{
+		"A": models.OutcomeSuccess,
+		"B": models.OutcomeFailed,
+		"C": models.OutcomeInconclusive,
+		"D": models.OutcomeSkipped,
+	}
+	if len(result.Executions) != len(want) {
+		t.Fatalf("Run() recorded %d executions, want %d", len(result.Executions), len(want))
+	}
+	for _, e := range result.Executions {
+		if e.Outcome != want[e.Plugin] {
+			t.Errorf("execution %s outcome = %s, want %s", e.Plugin, e.Outcome, want[e.Plugin])
+		}
+	}
+	if result.Executions[1].ErrorClass != models.ErrorClassTimeout {
+		t.Errorf("execution B error class = %q, want %q", result.Executions[1].ErrorClass, models.ErrorClassTimeout)
+	}
+	if got := len(result.Inconclusive()); got != 2 {
+		t.Errorf("Inconclusive() = %d, want 2", got)
+	}
+}
+
+type fakes []*fakePlugin
+
+func (f fakes) list() []plugins.Plugin {
+	list := make([]plugins.Plugin, len(f))
+	for i, p := range f {
+		list[i] = p
+	}
+	return list
+}
+
+func TestEngine_Stream(t *testing.T) {
+	eng := NewEngine(models.Config{MaxConcurrency: 2}, fakes{
+		&fakePlugin{name: "A", findings: []models.Finding{{PluginName: "A", Status: "exists"}}},
+		&fakePlugin{name: "B", err: errors.New("boom")},
+	}.list())
+
+	var subscribed int
+	eng.Subscribe(ObserverFunc(func(ev Event) { subscribed++ }))
+
+	counts := make(map[EventType]int)
+	var events []Event
+	for ev := range eng.Stream(context.Background(), "acme") {
+		counts[ev.Type]++
+		events = append(events, ev)
+	}
+
+	if events[0].Type != EventScanStarted || events[0].Total != 2 {
+		t.Errorf("first event = %+v, want scan_started with total 2", events[0])
+	}
+	last := events[len(events)-1]
+	if last.Type != EventScanFinished || last.Result == nil || last.Err == nil {
+		t.Errorf("last event = %+v, want scan_finished with result and error", last)
+	}
+
+	want := map[EventType]int{
+		EventScanStarted:    1,
+		EventPluginStarted:  2,
+		EventFinding:        2, // A's finding and B's error finding
+		EventError:          1,
+		EventPluginFinished: 2,
+		EventScanFinished:   1,
+	}
+	for typ, n := range want {
+		if counts[typ] != n {
+			t.Errorf("%s events = %d, want %d", typ, counts[typ], n)
+		}
+	}
+	if subscribed != len(events) {
+		t.Errorf("subscriber saw %d events, stream delivered %d", subscribed, len(events))
+	}
+}
+
+func TestEngine_StreamSlowConsumer(t *testing.T) {
+	many := make([]models.Finding, 40)
+	for i := range many {
+		many[i] = models.Finding{PluginName: "A", Status: "exists"}
+	}
+	eng := NewEngine(models.Config{MaxConcurrency: 1}, fakes{&fakePlugin{name: "A", findings: many}}.list())
+
+	// Nobody reads the first stream until the second one is done
+	stalled := eng.Stream(context.Background(), "stalled")
+
+	done := make(chan int)
+	go func() {
+		n := 0
+		for range eng.Stream(context.Background(), "acme") {
+			if n == 0 {
+				// Subscribing while draining must not deadlock
+				eng.Subscribe(ObserverFunc(func(Event) {}))
+			}
+			n++
+		}
+		done <- n
+	}()
+	select {
+	case n := <-done:
+		if n != len(many)+4 {
+			t.Errorf("stream delivered %d events, want %d", n, len(many)+4)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a stalled stream blocked another run")
+	}
+
+	n := 0
+	for range stalled {
+		n++
+	}
+	if n != len(many)+4 {
+		t.Errorf("stalled stream delivered %d events, want %d", n, len(many)+4)
+	}
+}
+
+func TestEngine_RunBatch(t *testing.T) {
+	eng := NewEngine(models.Config{MaxConcurrency: 3}, fakes{
+		&fakePlugin{name: "A", findings: []models.Finding{{PluginName: "A", Status: "exists"}}},
+		&fakePlugin{name: "B", err: errors.New("boom")},
+	}.list())
+
+	targets := []string{"one", "two", "three", "four"}
+	results, err := eng.RunBatch(context.Background(), targets)
+
+	if len(results) != len(targets) {
+		t.Fatalf("RunBatch() returned %d results, want %d", len(results), len(targets))
+	}
+	for i, res := range results {
+		if res.Target != targets[i] {
+			t.Errorf("results[%d].Target = %q, want %q", i, res.Target, targets[i])
+		}
+		if len(res.Executions) != 2 {
+			t.Errorf("results[%d] has %d executions, want 2", i, len(res.Executions))
+		}
+	}
+
+	var runErr *RunError
+	if !errors.As(err, &runErr) {
+		t.Fatalf("RunBatch() error = %v, want *RunError", err)
+	}
+	if len(runErr.Errors) != len(targets) {
+		t.Errorf("RunError has %d failures, want %d", len(runErr.Errors), len(targets))
+	}
+	for i, pe := range runErr.Errors {
+		if pe.Target != targets[i] {
+			t.Errorf("failure %d target = %q, want %q", i, pe.Target, targets[i])
+		}
+	}
+}
+
+func TestEngine_RunJobs(t *testing.T) {
+	eng := NewEngine(models.Config{MaxConcurrency: 2}, fakes{
+		&fakePlugin{name: "Twitter", findings: []models.Finding{{PluginName: "Twitter", Status: "exists"}}},
+		&fakePlugin{name: "GitHub", findings: []models.Finding{{PluginName: "GitHub", Status: "exists"}}},
+		&fakePlugin{name: "Instagram", findings: []models.Finding{{PluginName: "Instagram", Status: "exists"}}},
+	}.list())
+
+	jobs := []Job{
+		{Target: "all"},
+		{Target: "own", Plugins: []string{"github"}},
+		{Target: "two", Plugins: []string{"Instagram", "Twitter"}},
+		{Target: "none", Plugins: []string{"LinkedIn"}},
+	}
+	want := [][]string{
+		{"Twitter", "GitHub", "Instagram"},
+		{"GitHub"},
+		{"Twitter", "Instagram"},
+		nil,
+	}
+
+	results, err := eng.RunJobs(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("RunJobs() error = %v", err)
+	}
+	for i, res := range results {
+		var got []string
+		for _, ex := range res.Executions {
+			got = append(got, ex.Plugin)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want[i]) {
+			t.Errorf("job %s ran %v, want %v", jobs[i].Target, got, want[i])
+		}
+	}
+}
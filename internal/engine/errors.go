@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// PluginError is the failure of one plugin against one target
+type PluginError struct {
+	Plugin string
+	Target string
+	Class  string // one of the models.ErrorClass* constants
+	Err    error
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("%s [%s]: %v", e.Plugin, e.Target, e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// RunError collects every plugin failure of a run. The findings of the
+// plugins that succeeded are still returned alongside it.
+type RunError struct {
+	Errors []*PluginError
+}
+
+func (e *RunError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, pe := range e.Errors {
+		msgs[i] = pe.Error()
+	}
+	return fmt.Sprintf("%d check(s) failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap exposes the individual failures to errors.Is and errors.As
+func (e *RunError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, pe := range e.Errors {
+		errs[i] = pe
+	}
+	return errs
+}
+
+// Classify maps an error to one of the models.ErrorClass* constants
+func Classify(err error) string {
+	var se *httpx.StatusError
+	if errors.As(err, &se) {
+		if se.RateLimited() {
+			return models.ErrorClassRateLimited
+		}
+		return models.ErrorClassHTTPStatus
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return models.ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return models.ErrorClassCanceled
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return models.ErrorClassTimeout
+		}
+		return models.ErrorClassNetwork
+	}
+	var te *httpx.TransportError
+	if errors.As(err, &te) {
+		return models.ErrorClassNetwork
+	}
+
+	return models.ErrorClassInternal
+}
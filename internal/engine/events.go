@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// EventType identifies what happened during a scan
+type EventType string
+
+const (
+	EventScanStarted    EventType = "scan_started"
+	EventPluginStarted  EventType = "plugin_started"
+	EventPluginFinished EventType = "plugin_finished"
+	EventFinding        EventType = "finding"
+	EventError          EventType = "error"
+	EventScanFinished   EventType = "scan_finished"
+)
+
+// Event is a single progress notification. Only the fields relevant to the
+// event type are set.
+type Event struct {
+	Type      EventType
+	Time      time.Time
+	Target    string
+	Plugin    string             // plugin events, findings and errors
+	Total     int                // scan_started: number of plugin checks scheduled
+	Finding   *models.Finding    // finding
+	Execution *models.Execution  // plugin_finished
+	Err       error              // error: *PluginError; scan_finished: the error returned by Run
+	Result    *models.ScanResult // scan_finished
+}
+
+// Observer receives engine events. Calls are serialized by the engine, so
+// implementations need no locking of their own, but they should return
+// quickly as they block the reporting plugin. Subscribed observers share one
+// lock across runs; a per-run observer only waits for its own run.
+type Observer interface {
+	OnEvent(Event)
+}
+
+// ObserverFunc adapts a function to the Observer interface
+type ObserverFunc func(Event)
+
+func (f ObserverFunc) OnEvent(ev Event) {
+	f(ev)
+}
+
+// Subscribe registers an observer for all subsequent runs
+func (e *Engine) Subscribe(o Observer) {
+	e.obsMu.Lock()
+	defer e.obsMu.Unlock()
+	e.observers = append(e.observers, o)
+}
+
+// Stream runs the scan in the background and delivers its events on the
+// returned channel, which is closed after EventScanFinished. The caller must
+// drain the channel.
+func (e *Engine) Stream(ctx context.Context, target string) <-chan Event {
+	events := make(chan Event, 16)
+	go func() {
+		defer close(events)
+		e.run(ctx, Job{Target: target}, ObserverFunc(func(ev Event) { events <- ev }))
+	}()
+	return events
+}
+
+// serialized keeps the calls to an observer, made from the goroutines of
+// several plugins, from overlapping
+type serialized struct {
+	mu sync.Mutex
+	o  Observer
+}
+
+func (s *serialized) OnEvent(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.o.OnEvent(ev)
+}
+
+// emit delivers an event to the subscribed observers and an optional
+// per-run observer. Observers are called without obsMu held, so they may
+// subscribe others, and a slow per-run observer holds up its run only.
+func (e *Engine) emit(extra Observer, ev Event) {
+	ev.Time = time.Now()
+
+	e.obsMu.Lock()
+	observers := slices.Clone(e.observers)
+	e.obsMu.Unlock()
+
+	if len(observers) > 0 {
+		e.notifyMu.Lock()
+		for _, o := range observers {
+			o.OnEvent(ev)
+		}
+		e.notifyMu.Unlock()
+	}
+	if extra != nil {
+		extra.OnEvent(ev)
+	}
+}
@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// Export writes a graph to a file in the format its extension names:
+// .json, .graphml, or .dot and .gv for Graphviz
+func Export(g *models.Graph, filename string) error {
+	var write func(io.Writer, *models.Graph) error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		write = WriteJSON
+	case ".graphml":
+		write = WriteGraphML
+	case ".dot", ".gv":
+		write = WriteDOT
+	default:
+		return fmt.Errorf("unsupported graph format %q: use .json, .graphml, .dot or .gv", filepath.Ext(filename))
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(f, g); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteJSON writes a graph as JSON
+func WriteJSON(w io.Writer, g *models.Graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+type graphML struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []gmlKey `xml:"key"`
+	Graph   gmlGraph `xml:"graph"`
+}
+
+type gmlKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type gmlGraph struct {
+	ID          string    `xml:"id,attr"`
+	EdgeDefault string    `xml:"edgedefault,attr"`
+	Nodes       []gmlNode `xml:"node"`
+	Edges       []gmlEdge `xml:"edge"`
+}
+
+type gmlNode struct {
+	ID   string    `xml:"id,attr"`
+	Data []gmlData `xml:"data"`
+}
+
+type gmlEdge struct {
+	Source   string    `xml:"source,attr"`
+	Target   string    `xml:"target,attr"`
+	Directed string    `xml:"directed,attr,omitempty"`
+	Data     []gmlData `xml:"data"`
+}
+
+type gmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// data keeps the attributes that are set
+func data(kv ...string) []gmlData {
+	var out []gmlData
+	for i := 0; i < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			out = append(out, gmlData{Key: kv[i], Value: kv[i+1]})
+		}
+	}
+	return out
+}
+
+// WriteGraphML writes a graph as GraphML, readable by Gephi, yEd or
+// NetworkX; node and edge fields become attributes of the same name
+func WriteGraphML(w io.Writer, g *models.Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Graph: gmlGraph{ID: "socialrecon", EdgeDefault: "directed"},
+	}
+	for _, k := range []string{"kind", "label", "platform", "url", "status"} {
+		doc.Keys = append(doc.Keys, gmlKey{ID: k, For: "node", Name: k, Type: "string"})
+	}
+	for _, k := range []string{"kind", "detail"} {
+		doc.Keys = append(doc.Keys, gmlKey{ID: "edge_" + k, For: "edge", Name: k, Type: "string"})
+	}
+
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gmlNode{
+			ID:   n.ID,
+			Data: data("kind", string(n.Kind), "label", n.Label, "platform", n.Platform, "url", n.URL, "status", string(n.Status)),
+		})
+	}
+	for _, e := range g.Edges {
+		edge := gmlEdge{Source: e.From, Target: e.To, Data: data("edge_kind", string(e.Kind), "edge_detail", e.Detail)}
+		if e.Kind != models.EdgeLinksTo {
+			edge.Directed = "false"
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// shapes draws every kind of node differently in Graphviz
+var shapes = map[models.NodeKind]string{
+	models.NodeDomain:  "box",
+	models.NodeAccount: "ellipse",
+	models.NodeEmail:   "note",
+	models.NodeURL:     "plaintext",
+}
+
+// WriteDOT writes a graph in the Graphviz DOT language; undirected
+// relations are drawn dashed without arrows
+func WriteDOT(w io.Writer, g *models.Graph) error {
+	var sb strings.Builder
+	sb.WriteString("digraph socialrecon {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		label := n.Label
+		if n.Platform != "" {
+			label += "\n" + n.Platform
+		}
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=%s];\n", quote(n.ID), quote(label), shapes[n.Kind])
+	}
+	for _, e := range g.Edges {
+		attrs := "label=" + quote(string(e.Kind))
+		if e.Kind != models.EdgeLinksTo {
+			attrs += ", dir=none, style=dashed"
+		}
+		fmt.Fprintf(&sb, "  %s -> %s [%s];\n", quote(e.From), quote(e.To), attrs)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// quote makes a DOT string literal
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestWriteGraphML(t *testing.T) {
+	g := Build(scan())
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, g); err != nil {
+		t.Fatalf("WriteGraphML() error = %v", err)
+	}
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("WriteGraphML() wrote invalid XML: %v", err)
+	}
+	if len(doc.Graph.Nodes) != len(g.Nodes) || len(doc.Graph.Edges) != len(g.Edges) {
+		t.Errorf("GraphML has %d nodes and %d edges, want %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges), len(g.Nodes), len(g.Edges))
+	}
+	undirected := 0
+	for _, e := range doc.Graph.Edges {
+		if e.Directed == "false" {
+			undirected++
+		}
+	}
+	if undirected != 2 {
+		t.Errorf("GraphML has %d undirected edges, want the same-name and same-avatar ones", undirected)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := &models.Graph{
+		Nodes: []models.Node{
+			{ID: "domain:acme.example", Kind: models.NodeDomain, Label: "acme.example"},
+			{ID: "account:twitter:acme", Kind: models.NodeAccount, Label: `@acme "HQ"`, Platform: "Twitter"},
+		},
+		Edges: []models.Edge{
+			{From: "domain:acme.example", To: "account:twitter:acme", Kind: models.EdgeLinksTo},
+			{From: "account:twitter:acme", To: "domain:acme.example", Kind: models.EdgeSameName},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, g); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	for _, want := range []string{
+		`"domain:acme.example" [label="acme.example", shape=box];`,
+		`"account:twitter:acme" [label="@acme \"HQ\"\nTwitter", shape=ellipse];`,
+		`"domain:acme.example" -> "account:twitter:acme" [label="links_to"];`,
+		`[label="same_name", dir=none, style=dashed];`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("DOT output is missing %s:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	g := Build(scan())
+	dir := t.TempDir()
+
+	for _, name := range []string{"graph.json", "graph.graphml", "graph.dot", "graph.GV"} {
+		path := filepath.Join(dir, name)
+		if err := Export(g, path); err != nil {
+			t.Errorf("Export(%s) error = %v", name, err)
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("Export(%s) wrote nothing", name)
+		}
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "graph.json"))
+	var back models.Graph
+	if err := json.Unmarshal(data, &back); err != nil || len(back.Nodes) != len(g.Nodes) || len(back.Edges) != len(g.Edges) {
+		t.Errorf("JSON export does not read back: %v", err)
+	}
+
+	if err := Export(g, filepath.Join(dir, "graph.png")); err == nil {
+		t.Error("Export() accepted an unsupported format")
+	}
+}
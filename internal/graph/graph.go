@@ -0,0 +1,267 @@
+// Package graph correlates what a scan found into an identity graph: the
+// domain links to a Twitter account, whose bio links to a GitHub account,
+// whose profile links to a personal blog, and accounts sharing an avatar or
+// a display name are tied together.
+package graph
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/avatar"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/ownership"
+	"github.com/ismailtsdln/socialrecon/internal/scanner"
+)
+
+// Builder accumulates nodes and edges, each once
+type Builder struct {
+	g     models.Graph
+	nodes map[string]int
+	edges map[string]bool
+}
+
+// NewBuilder creates an empty graph builder
+func NewBuilder() *Builder {
+	return &Builder{nodes: make(map[string]int), edges: make(map[string]bool)}
+}
+
+// Node adds a node and returns its ID. A node already present keeps its
+// fields and takes those it was missing from n.
+func (b *Builder) Node(n models.Node) string {
+	i, ok := b.nodes[n.ID]
+	if !ok {
+		b.nodes[n.ID] = len(b.g.Nodes)
+		b.g.Nodes = append(b.g.Nodes, n)
+		return n.ID
+	}
+	have := &b.g.Nodes[i]
+	if have.Platform == "" {
+		have.Platform = n.Platform
+	}
+	if have.URL == "" {
+		have.URL = n.URL
+	}
+	if have.Status == "" {
+		have.Status = n.Status
+	}
+	return n.ID
+}
+
+// Edge adds an edge between two nodes already added. Undirected edges are
+// stored once whichever way they are added.
+func (b *Builder) Edge(e models.Edge) {
+	if e.From == e.To {
+		return
+	}
+	if e.Kind != models.EdgeLinksTo && e.From > e.To {
+		e.From, e.To = e.To, e.From
+	}
+	key := e.From + "\n" + e.To + "\n" + string(e.Kind)
+	if b.edges[key] {
+		return
+	}
+	b.edges[key] = true
+	b.g.Edges = append(b.g.Edges, e)
+}
+
+// Add merges a graph into the one being built
+func (b *Builder) Add(g *models.Graph) {
+	if g == nil {
+		return
+	}
+	for _, n := range g.Nodes {
+		b.Node(n)
+	}
+	for _, e := range g.Edges {
+		b.Edge(e)
+	}
+}
+
+// Graph returns the graph built so far
+func (b *Builder) Graph() *models.Graph {
+	g := models.Graph{Nodes: b.g.Nodes, Edges: b.g.Edges}
+	if g.Nodes == nil {
+		g.Nodes = []models.Node{}
+	}
+	if g.Edges == nil {
+		g.Edges = []models.Edge{}
+	}
+	return &g
+}
+
+// Merge combines graphs, such as those of a batch's targets, into one;
+// nodes found by several targets are joined
+func Merge(graphs ...*models.Graph) *models.Graph {
+	b := NewBuilder()
+	for _, g := range graphs {
+		b.Add(g)
+	}
+	return b.Graph()
+}
+
+var (
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	urlRe   = regexp.MustCompile(`https?://[^\s"'<>]+`)
+)
+
+// account is an account node with the profile that relates it to others
+type account struct {
+	id      string
+	profile *models.Profile
+}
+
+// Build returns the graph of a scan result: the scanned domain, every
+// account checked or linked, and the emails and URLs their profiles link to
+func Build(result *models.ScanResult) *models.Graph {
+	b := NewBuilder()
+	var root, domain string
+	if result.TargetType == models.TargetDomain {
+		domain = ownership.Host(result.Target)
+		root = b.Node(domainNode(domain))
+	}
+
+	var accounts []account
+	for _, id := range result.Identities {
+		// One finding per platform tells the state of the account
+		checked := make(map[string]models.Finding)
+		var order []string
+		for _, f := range id.Findings {
+			key := strings.ToLower(f.PluginName)
+			if _, ok := checked[key]; !ok && f.Status.Conclusive() && f.Status != models.StatusInvalidUsername {
+				checked[key] = f
+				order = append(order, key)
+			}
+		}
+
+		for _, src := range id.Sources {
+			from := root
+			if h := ownership.Host(src.Page); h != "" && h != domain {
+				from = b.Node(domainNode(h))
+			}
+			if from == "" {
+				continue
+			}
+			f := checked[strings.ToLower(src.Platform)]
+			to := b.Node(accountNode(src.Platform, id.Username, src.Profile, f.Status))
+			b.Edge(models.Edge{From: from, To: to, Kind: models.EdgeLinksTo, Detail: src.Page})
+		}
+
+		for _, key := range order {
+			f := checked[key]
+			if !taken(f.Status) {
+				continue
+			}
+			profileURL, _ := f.Metadata["url"].(string)
+			node := b.Node(accountNode(f.PluginName, id.Username, profileURL, f.Status))
+			if f.Profile == nil {
+				continue
+			}
+			accounts = append(accounts, account{id: node, profile: f.Profile})
+			for _, link := range f.Profile.Links {
+				b.link(node, link, "profile link", root, domain)
+			}
+			for _, link := range urlRe.FindAllString(f.Profile.Bio, -1) {
+				b.link(node, strings.TrimRight(link, ".,;:!?)"), "bio", root, domain)
+			}
+			for _, email := range emailRe.FindAllString(f.Profile.Bio, -1) {
+				b.Edge(models.Edge{From: node, To: b.Node(emailNode(email)), Kind: models.EdgeLinksTo, Detail: "bio"})
+			}
+		}
+	}
+
+	for i, a := range accounts {
+		for _, o := range accounts[i+1:] {
+			if a.id == o.id {
+				continue
+			}
+			if name := sameName(a.profile, o.profile); name != "" {
+				b.Edge(models.Edge{From: a.id, To: o.id, Kind: models.EdgeSameName, Detail: name})
+			}
+			if detail := sameAvatar(a.profile, o.profile); detail != "" {
+				b.Edge(models.Edge{From: a.id, To: o.id, Kind: models.EdgeSameAvatar, Detail: detail})
+			}
+		}
+	}
+
+	return b.Graph()
+}
+
+// link adds an edge from a node to what a link points to: an account when
+// it is a profile link, an email address, the scanned domain or the home
+// page of another site, or else the URL itself
+func (b *Builder) link(from, raw, detail, root, domain string) {
+	var to string
+	if addr, ok := strings.CutPrefix(raw, "mailto:"); ok {
+		addr, _, _ = strings.Cut(addr, "?")
+		to = b.Node(emailNode(addr))
+	} else if ident, ok := scanner.Resolve(raw); ok {
+		to = b.Node(accountNode(ident.Platform, ident.Username, ident.URL, ""))
+	} else if h := ownership.Host(raw); root != "" && (h == domain || strings.HasSuffix(h, "."+domain)) {
+		to = root
+	} else if u, err := url.Parse(raw); err == nil && h != "" && (u.Path == "" || u.Path == "/") && u.RawQuery == "" {
+		// A link to the home page of a site stands for the site
+		to = b.Node(domainNode(h))
+	} else {
+		to = b.Node(models.Node{ID: "url:" + raw, Kind: models.NodeURL, Label: raw, URL: raw})
+	}
+	b.Edge(models.Edge{From: from, To: to, Kind: models.EdgeLinksTo, Detail: detail})
+}
+
+// taken reports whether the status means an account holds the handle
+func taken(s models.Status) bool {
+	switch s {
+	case models.StatusExists, models.StatusPrivate, models.StatusAbandoned, models.StatusRestricted,
+		models.StatusSuspended, models.StatusDeactivated:
+		return true
+	}
+	return false
+}
+
+func domainNode(host string) models.Node {
+	return models.Node{ID: "domain:" + host, Kind: models.NodeDomain, Label: host}
+}
+
+func accountNode(platform, username, profileURL string, status models.Status) models.Node {
+	return models.Node{
+		ID:       "account:" + strings.ToLower(platform) + ":" + strings.ToLower(username),
+		Kind:     models.NodeAccount,
+		Label:    "@" + username,
+		Platform: platform,
+		URL:      profileURL,
+		Status:   status,
+	}
+}
+
+func emailNode(addr string) models.Node {
+	addr = strings.ToLower(addr)
+	return models.Node{ID: "email:" + addr, Kind: models.NodeEmail, Label: addr}
+}
+
+// sameName returns the display name two profiles share, ignoring case and
+// spacing
+func sameName(a, b *models.Profile) string {
+	norm := func(s string) string { return strings.ToLower(strings.Join(strings.Fields(s), " ")) }
+	if n := norm(a.DisplayName); n != "" && n == norm(b.DisplayName) {
+		return a.DisplayName
+	}
+	return ""
+}
+
+// sameAvatar tells how two profiles show the same picture, if they do
+func sameAvatar(a, b *models.Profile) string {
+	if a.AvatarURL != "" && a.AvatarURL == b.AvatarURL {
+		return "same image URL"
+	}
+	ha, errA := avatar.ParseHashes(a.AvatarHash)
+	hb, errB := avatar.ParseHashes(b.AvatarHash)
+	if errA != nil || errB != nil {
+		return ""
+	}
+	if d := ha.Distance(hb); d <= avatar.DefaultThreshold {
+		return fmt.Sprintf("hash distance %d", d)
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// scan is a domain scan where acme.example links to @acme on Twitter, whose
+// bio links to the GitHub organization, whose profile links to a blog
+func scan() *models.ScanResult {
+	return &models.ScanResult{
+		Target:     "https://www.acme.example",
+		TargetType: models.TargetDomain,
+		Identities: []models.Identity{
+			{
+				Username: "acme",
+				Sources: []models.Source{
+					{Platform: "Twitter", URL: "https://x.com/acme", Profile: "https://twitter.com/acme", Page: "https://acme.example/"},
+					{Platform: "Twitter", URL: "https://twitter.com/acme", Profile: "https://twitter.com/acme", Page: "https://acme.example/about"},
+				},
+				Findings: []models.Finding{
+					{
+						PluginName: "Twitter",
+						Status:     models.StatusExists,
+						Metadata:   map[string]interface{}{"url": "https://twitter.com/acme"},
+						Profile: &models.Profile{
+							DisplayName: "Acme Inc",
+							Bio:         "Code at https://github.com/acme-corp. Press: press@acme.example",
+							Links:       []string{"https://acme.example/"},
+							AvatarURL:   "https://cdn.example/a.png",
+						},
+					},
+				},
+			},
+			{
+				Username: "acme-corp",
+				Sources:  []models.Source{{Platform: "GitHub", URL: "https://github.com/acme-corp", Page: "https://acme.example/"}},
+				Findings: []models.Finding{
+					{
+						PluginName: "GitHub",
+						Status:     models.StatusExists,
+						Profile: &models.Profile{
+							DisplayName: "ACME  inc",
+							Links:       []string{"https://blog.jane.example/", "https://jane.example/talks/acme?lang=en", "mailto:oss@acme.example?subject=hi"},
+							AvatarURL:   "https://cdn.example/a.png",
+						},
+					},
+				},
+			},
+			{
+				Username: "acmeshop",
+				Sources:  []models.Source{{Platform: "Instagram", URL: "https://instagram.com/acmeshop", Page: "https://shop.acme.example/"}},
+				Findings: []models.Finding{{PluginName: "Instagram", Status: models.StatusAvailable}},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(scan())
+
+	nodes := map[string]models.NodeKind{
+		"domain:acme.example":                         models.NodeDomain,
+		"domain:shop.acme.example":                    models.NodeDomain,
+		"account:twitter:acme":                        models.NodeAccount,
+		"account:github:acme-corp":                    models.NodeAccount,
+		"account:instagram:acmeshop":                  models.NodeAccount,
+		"email:press@acme.example":                    models.NodeEmail,
+		"email:oss@acme.example":                      models.NodeEmail,
+		"domain:blog.jane.example":                    models.NodeDomain,
+		"url:https://jane.example/talks/acme?lang=en": models.NodeURL,
+	}
+	if len(g.Nodes) != len(nodes) {
+		t.Errorf("Build() returned %d nodes, want %d: %+v", len(g.Nodes), len(nodes), g.Nodes)
+	}
+	for id, kind := range nodes {
+		n, ok := g.Node(id)
+		if !ok || n.Kind != kind {
+			t.Errorf("node %s = %+v, %v, want kind %s", id, n, ok, kind)
+		}
+	}
+	if n, _ := g.Node("account:instagram:acmeshop"); n.Status != models.StatusAvailable {
+		t.Errorf("dead link target status = %q, want available", n.Status)
+	}
+
+	edges := []models.Edge{
+		{From: "domain:acme.example", To: "account:twitter:acme", Kind: models.EdgeLinksTo, Detail: "https://acme.example/"},
+		{From: "domain:acme.example", To: "account:github:acme-corp", Kind: models.EdgeLinksTo, Detail: "https://acme.example/"},
+		{From: "domain:shop.acme.example", To: "account:instagram:acmeshop", Kind: models.EdgeLinksTo, Detail: "https://shop.acme.example/"},
+		{From: "account:twitter:acme", To: "domain:acme.example", Kind: models.EdgeLinksTo, Detail: "profile link"},
+		{From: "account:twitter:acme", To: "account:github:acme-corp", Kind: models.EdgeLinksTo, Detail: "bio"},
+		{From: "account:twitter:acme", To: "email:press@acme.example", Kind: models.EdgeLinksTo, Detail: "bio"},
+		{From: "account:github:acme-corp", To: "domain:blog.jane.example", Kind: models.EdgeLinksTo, Detail: "profile link"},
+		{From: "account:github:acme-corp", To: "url:https://jane.example/talks/acme?lang=en", Kind: models.EdgeLinksTo, Detail: "profile link"},
+		{From: "account:github:acme-corp", To: "email:oss@acme.example", Kind: models.EdgeLinksTo, Detail: "profile link"},
+		{From: "account:github:acme-corp", To: "account:twitter:acme", Kind: models.EdgeSameName, Detail: "Acme Inc"},
+		{From: "account:github:acme-corp", To: "account:twitter:acme", Kind: models.EdgeSameAvatar, Detail: "same image URL"},
+	}
+	for _, want := range edges {
+		found := false
+		for _, e := range g.Edges {
+			if e == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Build() is missing edge %+v", want)
+		}
+	}
+	if len(g.Edges) != len(edges) {
+		t.Errorf("Build() returned %d edges, want %d: %+v", len(g.Edges), len(edges), g.Edges)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	username := &models.ScanResult{
+		Target:     "acme",
+		TargetType: models.TargetUsername,
+		Identities: []models.Identity{{
+			Username: "acme",
+			Findings: []models.Finding{{PluginName: "Twitter", Status: models.StatusExists, Profile: &models.Profile{Links: []string{"https://www.acme.example"}}}},
+		}},
+	}
+
+	domain := Build(scan())
+	g := Merge(domain, Build(username))
+
+	// The username scan found the same account linking to the same site
+	if len(g.Nodes) != len(domain.Nodes) {
+		t.Errorf("Merge() returned %d nodes, want the domain scan's %d", len(g.Nodes), len(domain.Nodes))
+	}
+	if len(g.Edges) != len(domain.Edges) {
+		t.Errorf("Merge() returned %d edges, want %d", len(g.Edges), len(domain.Edges))
+	}
+}
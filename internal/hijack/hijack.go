@@ -0,0 +1,84 @@
+// Package hijack detects broken social link hijacking: a site linking to a
+// social account that no longer exists, whose handle anyone can register
+// and use to impersonate the site's owner.
+package hijack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// Indicator identifies broken social link findings
+const Indicator = "broken_social_link"
+
+// Detect returns a finding for every link that revealed the identity and
+// points to a platform where the handle was found to be available. The
+// identity's findings must come from checking it on its own platforms.
+func Detect(id models.Identity) []models.Finding {
+	var findings []models.Finding
+	seen := make(map[string]bool)
+
+	for _, src := range id.Sources {
+		check := availability(id.Findings, src.Platform)
+		if check == nil {
+			continue
+		}
+		key := src.Page + "\n" + src.URL
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		meta := map[string]interface{}{
+			"referring_page": src.Page,
+			"link":           src.URL,
+		}
+		if src.Profile != "" {
+			meta["profile_url"] = src.Profile
+		}
+		if src.Via != "" {
+			meta["via"] = src.Via
+		}
+		if len(src.Redirects) > 0 {
+			meta["redirects"] = src.Redirects
+		}
+		// Keep the evidence of the availability check
+		for _, k := range []string{"url", "final_url", "http_status", "evidence"} {
+			if v, ok := check.Metadata[k]; ok {
+				meta["check_"+k] = v
+			}
+		}
+
+		page := src.Page
+		if page == "" {
+			page = "The site"
+		}
+		findings = append(findings, models.Finding{
+			PluginName:  check.PluginName,
+			Indicator:   Indicator,
+			Value:       id.Username,
+			Status:      models.StatusAvailable,
+			Severity:    models.SeverityCritical,
+			Description: fmt.Sprintf("%s links to %s, but the %s handle '%s' is unregistered and can be claimed by anyone", page, src.URL, check.PluginName, id.Username),
+			Metadata:    meta,
+			Timestamp:   time.Now(),
+		})
+	}
+
+	return findings
+}
+
+// availability returns the finding reporting the handle as available on the
+// platform, if any
+func availability(findings []models.Finding, platform string) *models.Finding {
+	for i := range findings {
+		f := &findings[i]
+		if f.Status == models.StatusAvailable && f.Indicator != Indicator && strings.EqualFold(f.PluginName, platform) {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package hijack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestDetect(t *testing.T) {
+	available := models.Finding{
+		PluginName: "Twitter",
+		Indicator:  "twitter_profile",
+		Status:     models.StatusAvailable,
+		Metadata: map[string]interface{}{
+			"url":         "https://twitter.com/acme",
+			"http_status": 404,
+			"evidence":    []string{"status 404"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		id    models.Identity
+		pages []string
+	}{
+		{
+			name: "Dead link on two pages",
+			id: models.Identity{
+				Username: "acme",
+				Sources: []models.Source{
+					{Platform: "Twitter", URL: "https://x.com/acme", Page: "https://acme.example/", Via: "anchor"},
+					{Platform: "Twitter", URL: "https://x.com/acme", Page: "https://acme.example/", Via: "meta"},
+					{Platform: "Twitter", URL: "https://twitter.com/acme", Page: "https://acme.example/about"},
+				},
+				Findings: []models.Finding{available},
+			},
+			pages: []string{"https://acme.example/", "https://acme.example/about"},
+		},
+		{
+			name: "Available only on another platform",
+			id: models.Identity{
+				Username: "acme",
+				Sources:  []models.Source{{Platform: "GitHub", URL: "https://github.com/acme", Page: "https://acme.example/"}},
+				Findings: []models.Finding{available},
+			},
+		},
+		{
+			name: "Linked account exists",
+			id: models.Identity{
+				Username: "acme",
+				Sources:  []models.Source{{Platform: "Twitter", URL: "https://twitter.com/acme", Page: "https://acme.example/"}},
+				Findings: []models.Finding{{PluginName: "Twitter", Indicator: "twitter_profile", Status: models.StatusExists}},
+			},
+		},
+		{
+			name: "Handle given directly",
+			id:   models.Identity{Username: "acme", Findings: []models.Finding{available}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Detect(tt.id)
+			if len(findings) != len(tt.pages) {
+				t.Fatalf("Detect() returned %d findings, want %d: %v", len(findings), len(tt.pages), findings)
+			}
+			for i, f := range findings {
+				if f.Indicator != Indicator || f.Severity != models.SeverityCritical || f.Status != models.StatusAvailable {
+					t.Errorf("finding %d = %+v, want a critical %s", i, f, Indicator)
+				}
+				if f.Metadata["referring_page"] != tt.pages[i] {
+					t.Errorf("referring_page = %v, want %s", f.Metadata["referring_page"], tt.pages[i])
+				}
+				if f.Metadata["check_http_status"] != 404 || f.Metadata["link"] == nil {
+					t.Errorf("metadata = %v, want the link and the check evidence", f.Metadata)
+				}
+				if !strings.Contains(f.Description, tt.pages[i]) {
+					t.Errorf("description %q does not name the referring page", f.Description)
+				}
+			}
+		})
+	}
+}
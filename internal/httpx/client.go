@@ -0,0 +1,124 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/ratelimit"
+)
+
+// Client is the outbound HTTP client shared by all plugins of a run. Every
+// request, including redirect hops, waits for its destination host's
+// rate limiter.
+type Client struct {
+	client  *http.Client
+	limiter *ratelimit.Limiter
+	retry   RetryPolicy
+}
+
+// NewClient creates a client throttled by limiter and retrying transient
+// failures according to retry. A nil limiter disables throttling.
+func NewClient(timeout time.Duration, limiter *ratelimit.Limiter, retry RetryPolicy) *Client {
+	if limiter == nil {
+		limiter = ratelimit.NewLimiter(0, 0)
+	}
+	return &Client{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &limitedTransport{
+				base:    http.DefaultTransport,
+				limiter: limiter,
+			},
+		},
+		limiter: limiter,
+		retry:   retry,
+	}
+}
+
+// Do sends a request once the destination host's rate limit allows it,
+// retrying transient failures with jittered exponential backoff. Time spent
+// waiting does not count towards the client timeout.
+//
+// A response is returned for any status code once retries are exhausted;
+// callers that cannot interpret it should report resp.StatusError(). Failures
+// without a response are returned as *TransportError.
+func (c *Client) Do(req *http.Request) (*Response, error) {
+	ctx := req.Context()
+	host := req.URL.Hostname()
+
+	maxAttempts := c.retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx, host); err != nil {
+			return nil, &TransportError{Host: host, Attempts: attempt - 1, Err: err}
+		}
+
+		r := req
+		if attempt > 1 {
+			r = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, &TransportError{Host: host, Attempts: attempt - 1, Err: err}
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := c.client.Do(r)
+		if err != nil {
+			if attempt >= maxAttempts || !retryableError(ctx, err) {
+				return nil, &TransportError{Host: host, Attempts: attempt, Err: err}
+			}
+			if err := sleep(ctx, c.retry.backoff(attempt)); err != nil {
+				return nil, &TransportError{Host: host, Attempts: attempt, Err: err}
+			}
+			continue
+		}
+
+		if attempt >= maxAttempts || !retryableStatus(resp) {
+			return &Response{Response: resp, Attempts: attempt}, nil
+		}
+
+		delay := c.retry.backoff(attempt)
+		if hint := retryAfter(resp, time.Now()); hint > 0 {
+			if c.retry.MaxDelay > 0 && hint > c.retry.MaxDelay {
+				// Not worth blocking the scan for; let the caller report it
+				return &Response{Response: resp, Attempts: attempt}, nil
+			}
+			delay = hint
+		}
+
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, &TransportError{Host: host, Attempts: attempt, Err: err}
+		}
+	}
+}
+
+// Limiter returns the limiter the client is throttled by
+func (c *Client) Limiter() *ratelimit.Limiter {
+	return c.limiter
+}
+
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *ratelimit.Limiter
+}
+
+// RoundTrip throttles redirect hops; the initial request was already
+// throttled by Client.Do
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Response != nil {
+		if err := t.limiter.Wait(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
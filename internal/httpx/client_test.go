@@ -0,0 +1,251 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClient_Do(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []func(w http.ResponseWriter)
+		wantStatus   int
+		wantAttempts int
+	}{
+		{
+			name: "Server error then success",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name: "Not found is permanent",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			},
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		{
+			name: "Plain forbidden is permanent",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusForbidden) },
+			},
+			wantStatus:   http.StatusForbidden,
+			wantAttempts: 1,
+		},
+		{
+			name: "Exhausted rate limit is retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name: "Retries exhausted",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+			},
+			wantStatus:   http.StatusTooManyRequests,
+			wantAttempts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&calls, 1)) - 1
+				if n >= len(tt.responses) {
+					t.Errorf("unexpected attempt %d", n+1)
+					return
+				}
+				tt.responses[n](w)
+			}))
+			defer srv.Close()
+
+			c := NewClient(time.Second, nil, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+			req, _ := http.NewRequest("GET", srv.URL, nil)
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus || resp.Attempts != tt.wantAttempts {
+				t.Errorf("Do() = status %d after %d attempts, want %d after %d",
+					resp.StatusCode, resp.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestClient_DoRetryAfterTooLong(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := NewClient(time.Second, nil, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Errorf("server called %d times, want 1", calls)
+	}
+	se := resp.StatusError()
+	if !se.RateLimited() || se.RetryAfter != time.Hour {
+		t.Errorf("StatusError() = %+v, want rate limited with 1h hint", se)
+	}
+}
+
+func TestClient_DoTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	c := NewClient(time.Second, nil, RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	req, _ := http.NewRequest("GET", url, nil)
+	_, err := c.Do(req)
+
+	var te *TransportError
+	if !errors.As(err, &te) {
+		t.Fatalf("Do() error = %v, want *TransportError", err)
+	}
+	if got := Attempts(err); got != 2 {
+		t.Errorf("Attempts() = %d, want 2", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{name: "Seconds", headers: map[string]string{"Retry-After": "7"}, want: 7 * time.Second},
+		{name: "HTTP date", headers: map[string]string{"Retry-After": now.Add(time.Minute).UTC().Format(http.TimeFormat)}, want: time.Minute},
+		{name: "Rate limit reset", headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000090"}, want: 90 * time.Second},
+		{name: "Reset with quota left", headers: map[string]string{"X-RateLimit-Remaining": "5", "X-RateLimit-Reset": "1700000090"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+			if got := retryAfter(resp, now); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryableError(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+	truncated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, _ := w.(http.Hijacker).Hijack()
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n")
+		buf.Flush()
+		conn.Close()
+	}))
+	defer truncated.Close()
+	hangup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer hangup.Close()
+	loop := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	defer loop.Close()
+	untrusted := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	untrusted.Config.ErrorLog = log.New(io.Discard, "", 0)
+	untrusted.StartTLS()
+	defer untrusted.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closed.Close()
+
+	// get returns the error of a plain GET, as Client.Do sees it
+	get := func(ctx context.Context, timeout time.Duration, url string) error {
+		req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+		resp, err := (&http.Client{Timeout: timeout}).Do(req)
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("GET %s succeeded", url)
+		}
+		return err
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelExpired()
+	<-expired.Done()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{name: "Client timeout", err: get(context.Background(), 50*time.Millisecond, slow.URL), want: true},
+		{name: "Connection refused", err: get(context.Background(), time.Second, closed.URL), want: true},
+		{name: "Connection reset", err: &url.Error{Op: "Get", URL: slow.URL, Err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, want: true},
+		{name: "Closed before responding", err: get(context.Background(), time.Second, hangup.URL), want: true},
+		{name: "Truncated response", err: get(context.Background(), time.Second, truncated.URL), want: true},
+		{name: "Untrusted certificate", err: get(context.Background(), time.Second, untrusted.URL), want: false},
+		{name: "Unsupported scheme", err: get(context.Background(), time.Second, "ftp://example.com/"), want: false},
+		{name: "Redirect limit", err: get(context.Background(), time.Second, loop.URL), want: false},
+		{name: "Caller canceled", ctx: canceled, err: get(canceled, time.Second, slow.URL), want: false},
+		{name: "Caller deadline", ctx: expired, err: get(expired, time.Second, slow.URL), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if got := retryableError(ctx, tt.err); got != tt.want {
+				t.Errorf("retryableError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how transient failures are retried
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first, values < 1 mean 1
+	BaseDelay   time.Duration // backoff before the second attempt, doubled on each retry
+	MaxDelay    time.Duration // upper bound for a single wait; longer Retry-After hints are not waited out
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+	}
+}
+
+// backoff returns the jittered exponential delay after the given attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Equal jitter: half fixed, half random
+	return d/2 + rand.N(d/2+1)
+}
+
+// Response is an HTTP response together with the number of attempts it took
+type Response struct {
+	*http.Response
+	Attempts int
+}
+
+// StatusError reports a response whose status code the caller could not
+// interpret, typically a rate limit or server error that outlived retries
+type StatusError struct {
+	Host       string
+	StatusCode int
+	Attempts   int
+	RetryAfter time.Duration // server-provided wait hint, if any
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%s returned status %d after %d attempt(s)", e.Host, e.StatusCode, e.Attempts)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %v", e.RetryAfter.Round(time.Second))
+	}
+	return msg
+}
+
+// RateLimited reports whether the status indicates throttling
+func (e *StatusError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests || (e.StatusCode == http.StatusForbidden && e.RetryAfter > 0)
+}
+
+// StatusError builds a StatusError for the response
+func (r *Response) StatusError() *StatusError {
+	return &StatusError{
+		Host:       r.Request.URL.Hostname(),
+		StatusCode: r.StatusCode,
+		Attempts:   r.Attempts,
+		RetryAfter: retryAfter(r.Response, time.Now()),
+	}
+}
+
+// TransportError reports a request that failed without a response
+type TransportError struct {
+	Host     string
+	Attempts int
+	Err      error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("request to %s failed after %d attempt(s): %v", e.Host, e.Attempts, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Attempts returns the number of attempts recorded on an httpx error, or 0
+func Attempts(err error) int {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Attempts
+	}
+	var te *TransportError
+	if errors.As(err, &te) {
+		return te.Attempts
+	}
+	return 0
+}
+
+// retryableStatus reports whether a response is worth retrying. 403 is only
+// retried when the server signals an exhausted rate limit.
+func retryableStatus(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	return false
+}
+
+// retryableError reports whether a transport failure is transient: a
+// timeout, a refused, reset or closed connection, or a response cut short.
+// Failures that would only repeat, such as a bad certificate, an unsupported
+// scheme or too many redirects, are not retried, and neither is anything once
+// the caller's context is done. A client timeout only ends one attempt.
+func retryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter extracts the server's wait hint from Retry-After (seconds or
+// HTTP date) or X-RateLimit-Reset (unix seconds) when the limit is exhausted
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if v := resp.Header.Get("X-RateLimit-Reset"); v != "" {
+			if epoch, err := strconv.ParseInt(v, 10, 64); err == nil {
+				if t := time.Unix(epoch, 0); t.After(now) {
+					return t.Sub(now)
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"maps"
+	"time"
+)
 
 // Severity defines the risk level of a finding
 type Severity string
@@ -13,30 +16,301 @@ const (
 	SeverityCritical Severity = "CRITICAL"
 )
 
+// Status is the outcome of a check for a single account
+type Status string
+
+const (
+	StatusExists          Status = "exists"
+	StatusAvailable       Status = "available"
+	StatusSuspended       Status = "suspended"
+	StatusDeactivated     Status = "deactivated"
+	StatusPrivate         Status = "private"
+	StatusRestricted      Status = "restricted"       // temporarily limited or behind a platform warning
+	StatusUnknown         Status = "unknown"          // the platform answered but the response was ambiguous
+	StatusRateLimited     Status = "rate_limited"     // the platform throttled or blocked the check
+	StatusError           Status = "error"            // the check failed
+	StatusInvalidUsername Status = "invalid_username" // the handle cannot exist on the platform
+	StatusAbandoned       Status = "abandoned"        // exists but inactive beyond the staleness policy
+)
+
+// Conclusive reports whether the status answers the question "does this
+// account exist and in what state"
+func (s Status) Conclusive() bool {
+	switch s {
+	case StatusUnknown, StatusRateLimited, StatusError:
+		return false
+	}
+	return true
+}
+
 // Finding represents a single discovery by a plugin
 type Finding struct {
-	PluginName  string    `json:"plugin_name"`
-	Indicator   string    `json:"indicator"`   // e.g., "twitter.com/user"
-	Value       string    `json:"value"`       // e.g., "johndoe"
-	Status      string    `json:"status"`      // e.g., "exists", "available", "suspended"
-	Severity    Severity  `json:"severity"`
-	Description string    `json:"description"`
+	PluginName  string                 `json:"plugin_name"`
+	Indicator   string                 `json:"indicator"` // e.g., "twitter.com/user"
+	Value       string                 `json:"value"`     // e.g., "johndoe"
+	Status      Status                 `json:"status"`
+	Severity    Severity               `json:"severity"`
+	Description string                 `json:"description"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
+	Profile     *Profile               `json:"profile,omitempty"` // details of the account, when the platform shows them
+	Timestamp   time.Time              `json:"timestamp"`
+}
+
+// SetMeta sets a metadata key on a copy of the finding's metadata. A handle
+// is scanned once and its findings are copied to every identity holding it,
+// under any target, so the map may be shared and must not be written in
+// place.
+func (f *Finding) SetMeta(key string, value interface{}) {
+	meta := make(map[string]interface{}, len(f.Metadata)+1)
+	maps.Copy(meta, f.Metadata)
+	meta[key] = value
+	f.Metadata = meta
+}
+
+// Profile holds what a platform shows about an account. Fields the platform
+// did not reveal are left empty; counts are nil when unknown rather than 0.
+type Profile struct {
+	DisplayName     string     `json:"display_name,omitempty"`
+	Bio             string     `json:"bio,omitempty"`
+	Links           []string   `json:"links,omitempty"`            // external links listed on the profile
+	VerifiedDomains []string   `json:"verified_domains,omitempty"` // domains the platform attests the account controls
+	AvatarURL       string     `json:"avatar_url,omitempty"`
+	AvatarHash      string     `json:"avatar_hash,omitempty"` // perceptual hashes of the avatar, when downloaded
+	Followers       *int64     `json:"followers,omitempty"`
+	Following       *int64     `json:"following,omitempty"`
+	Posts           *int64     `json:"posts,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	LastActive      *time.Time `json:"last_active,omitempty"` // latest post, commit or other event seen
+	Location        string     `json:"location,omitempty"`
+	Verified        bool       `json:"verified,omitempty"`
+}
+
+// Outcome describes how a single plugin execution ended
+type Outcome string
+
+const (
+	OutcomeSuccess      Outcome = "success"      // conclusive findings were produced
+	OutcomeInconclusive Outcome = "inconclusive" // the plugin ran but could not decide
+	OutcomeSkipped      Outcome = "skipped"      // the target is not applicable to the plugin
+	OutcomeFailed       Outcome = "failed"       // the plugin returned an error
+)
+
+// Error classes recorded on failed executions
+const (
+	ErrorClassRateLimited = "rate_limited"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassNetwork     = "network"
+	ErrorClassHTTPStatus  = "http_status"
+	ErrorClassInternal    = "internal"
+)
+
+// Execution records one plugin run against one target
+type Execution struct {
+	Plugin     string        `json:"plugin"`
+	Target     string        `json:"target"`
+	Duration   time.Duration `json:"duration_ns"`
+	Outcome    Outcome       `json:"outcome"`
+	ErrorClass string        `json:"error_class,omitempty"`
+	Message    string        `json:"message,omitempty"`
+	Attempts   int           `json:"attempts,omitempty"`
+}
+
+// Source records where an identity was discovered
+type Source struct {
+	Platform string `json:"platform,omitempty"` // platform the link points to
+	URL      string `json:"url,omitempty"`      // the link that revealed the identity
+	Profile  string `json:"profile,omitempty"`  // canonical profile URL the link resolves to
+	Page     string `json:"page,omitempty"`     // the page the link was found on
+	Via      string `json:"via,omitempty"`      // how the link was extracted, e.g. anchor, meta, json-ld
+	// URLs the link led through to the profile, when it was a redirect or
+	// shortened link
+	Redirects []string `json:"redirects,omitempty"`
+}
+
+// Ownership tells whether an identity found on a domain belongs to the
+// domain's owner
+type Ownership string
+
+const (
+	OwnershipVerified  Ownership = "verified"        // the domain and an account link to each other, or the platform attests the domain
+	OwnershipOneWay    Ownership = "claimed_one_way" // only one side links to the other
+	OwnershipUnrelated Ownership = "unrelated"       // neither links to the other
+)
+
+// Permutation records how a lookalike handle was derived from a brand name
+type Permutation struct {
+	Of        string `json:"of"`        // the brand handle it imitates
+	Transform string `json:"transform"` // e.g. affix, omission, digit_substitution
+	Detail    string `json:"detail,omitempty"`
 }
 
-// ScanResult is the final output of a scan
+// Identity is a handle belonging to a root target, either given directly,
+// discovered from the target's web presence or generated as a lookalike of
+// it, with the findings of checking it
+type Identity struct {
+	Username    string       `json:"username"`
+	Sources     []Source     `json:"sources,omitempty"`     // empty when the handle was the input itself
+	Permutation *Permutation `json:"permutation,omitempty"` // set on impersonation candidates
+	Ownership   Ownership    `json:"ownership,omitempty"`   // whether the scanned domain's owner holds the accounts
+	Findings    []Finding    `json:"findings"`
+	Executions  []Execution  `json:"executions,omitempty"`
+}
+
+// ScanResult is the final output of a scan. Findings about the target as a
+// whole live in Findings; findings about a particular handle live under the
+// corresponding identity.
 type ScanResult struct {
-	Target    string    `json:"target"`
-	Findings  []Finding `json:"findings"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-	RiskScore float64   `json:"risk_score"`
+	Target     string      `json:"target"`
+	TargetType TargetType  `json:"target_type,omitempty"`
+	Findings   []Finding   `json:"findings"`
+	Identities []Identity  `json:"identities,omitempty"`
+	Executions []Execution `json:"executions,omitempty"`
+	StartTime  time.Time   `json:"start_time"`
+	EndTime    time.Time   `json:"end_time"`
+	RiskScore  float64     `json:"risk_score"`
+	Graph      *Graph      `json:"graph,omitempty"` // how the domains, accounts, emails and URLs found relate
+}
+
+// EachFinding calls fn for every finding of the result, root-level first,
+// then per identity. fn may modify the finding in place.
+func (r *ScanResult) EachFinding(fn func(*Finding)) {
+	for i := range r.Findings {
+		fn(&r.Findings[i])
+	}
+	for i := range r.Identities {
+		for j := range r.Identities[i].Findings {
+			fn(&r.Identities[i].Findings[j])
+		}
+	}
+}
+
+// FindingCount returns the number of findings across the whole tree
+func (r *ScanResult) FindingCount() int {
+	n := len(r.Findings)
+	for _, id := range r.Identities {
+		n += len(id.Findings)
+	}
+	return n
+}
+
+// Inconclusive returns the executions, across the whole tree, that did not
+// produce a conclusive answer
+func (r *ScanResult) Inconclusive() []Execution {
+	list := inconclusive(r.Executions)
+	for _, id := range r.Identities {
+		list = append(list, inconclusive(id.Executions)...)
+	}
+	return list
+}
+
+func inconclusive(execs []Execution) []Execution {
+	var list []Execution
+	for _, e := range execs {
+		if e.Outcome == OutcomeFailed || e.Outcome == OutcomeInconclusive {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// ExecutionCount returns the number of plugin executions across the tree
+func (r *ScanResult) ExecutionCount() int {
+	n := len(r.Executions)
+	for _, id := range r.Identities {
+		n += len(id.Executions)
+	}
+	return n
+}
+
+// TargetType tells how a scan target is interpreted
+type TargetType string
+
+const (
+	TargetAuto     TargetType = "auto" // classified from its shape
+	TargetDomain   TargetType = "domain"
+	TargetUsername TargetType = "username"
+)
+
+// NodeKind is the type of a graph node
+type NodeKind string
+
+const (
+	NodeDomain  NodeKind = "domain"
+	NodeAccount NodeKind = "account"
+	NodeEmail   NodeKind = "email"
+	NodeURL     NodeKind = "url"
+)
+
+// EdgeKind is the type of a relation between two graph nodes
+type EdgeKind string
+
+const (
+	EdgeLinksTo    EdgeKind = "links_to"    // the source links or refers to the target
+	EdgeSameAvatar EdgeKind = "same_avatar" // both accounts show the same picture
+	EdgeSameName   EdgeKind = "same_name"   // both accounts use the same display name
+)
+
+// Graph relates what a scan found: the domain links to an account, whose
+// bio links to another account, whose profile links to a blog
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Node is a domain, account, email address or URL of the graph
+type Node struct {
+	ID       string   `json:"id"`
+	Kind     NodeKind `json:"kind"`
+	Label    string   `json:"label"`
+	Platform string   `json:"platform,omitempty"` // accounts only
+	URL      string   `json:"url,omitempty"`
+	Status   Status   `json:"status,omitempty"` // accounts only, when checked
+}
+
+// Edge is a relation between two nodes. Same-avatar and same-name edges
+// are undirected.
+type Edge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Kind   EdgeKind `json:"kind"`
+	Detail string   `json:"detail,omitempty"` // e.g. the page carrying the link
+}
+
+// Node returns the node with the given ID
+func (g *Graph) Node(id string) (Node, bool) {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
+
+// Target is a single input of a scan
+type Target struct {
+	Value string     `json:"value"`
+	Type  TargetType `json:"type"`
+}
+
+// BatchResult groups the results of a multi-target scan, one ScanResult per
+// input target in input order
+type BatchResult struct {
+	Results   []*ScanResult `json:"results"`
+	StartTime time.Time     `json:"start_time"`
+	EndTime   time.Time     `json:"end_time"`
+	RiskScore float64       `json:"risk_score"` // highest score of any target
 }
 
 // Config holds engine configuration
 type Config struct {
-	MaxConcurrency int
-	Timeout        time.Duration
-	RateLimit      int // requests per second
+	MaxConcurrency int                // plugin checks in flight across all targets
+	Timeout        time.Duration      // per target scan, 0 = no limit
+	RequestTimeout time.Duration      // per HTTP request, defaults to 10s
+	RateLimit      float64            // requests per second per destination host, 0 = unlimited
+	RateBurst      int                // bucket size, defaults to the rate rounded up
+	PluginRates    map[string]float64 // per-plugin overrides keyed by plugin name (case-insensitive)
+	MaxAttempts    int                // attempts per request including retries, defaults to 3
+	RetryBaseDelay time.Duration      // first backoff delay, defaults to 1s
+	RetryMaxDelay  time.Duration      // longest single wait, defaults to 30s
 }
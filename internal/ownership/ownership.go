@@ -0,0 +1,131 @@
+// Package ownership checks whether the accounts a domain links to belong to
+// the domain's owner: an account is only trustworthy when it points back to
+// the domain, from its website or bio, rel="me" and pinned links, or a domain
+// the platform verified.
+package ownership
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// rank orders the classifications from the weakest
+var rank = map[models.Ownership]int{
+	models.OwnershipUnrelated: 1,
+	models.OwnershipOneWay:    2,
+	models.OwnershipVerified:  3,
+}
+
+// Verify classifies the accounts of an identity found while scanning a
+// domain. Each account finding records its classification and the evidence
+// in "ownership" and "ownership_evidence"; the identity takes the strongest
+// one. Identities without any account are left unclassified.
+func Verify(domain string, id *models.Identity) {
+	domain = Host(domain)
+	if domain == "" {
+		return
+	}
+
+	for i := range id.Findings {
+		f := &id.Findings[i]
+		switch f.Status {
+		case models.StatusExists, models.StatusPrivate, models.StatusAbandoned, models.StatusRestricted:
+		default:
+			continue
+		}
+
+		var evidence []string
+		linked := false
+		for _, src := range id.Sources {
+			if strings.EqualFold(src.Platform, f.PluginName) {
+				linked = true
+				evidence = append(evidence, "linked from "+orDomain(src.Page, domain))
+				break
+			}
+		}
+		back, attested := backLinks(f.Profile, domain)
+		evidence = append(evidence, back...)
+
+		var o models.Ownership
+		switch {
+		case attested || linked && len(back) > 0:
+			o = models.OwnershipVerified
+		case linked || len(back) > 0:
+			o = models.OwnershipOneWay
+		default:
+			o = models.OwnershipUnrelated
+		}
+
+		f.SetMeta("ownership", o)
+		if len(evidence) > 0 {
+			f.SetMeta("ownership_evidence", evidence)
+		}
+		if rank[o] > rank[id.Ownership] {
+			id.Ownership = o
+		}
+	}
+}
+
+// backLinks returns how a profile points back to the domain, and whether
+// the platform itself attests the account controls it
+func backLinks(p *models.Profile, domain string) ([]string, bool) {
+	if p == nil {
+		return nil, false
+	}
+	var evidence []string
+	attested := false
+	for _, d := range p.VerifiedDomains {
+		if matches(Host(d), domain) {
+			evidence = append(evidence, fmt.Sprintf("platform verified the domain %s", d))
+			attested = true
+		}
+	}
+	for _, link := range p.Links {
+		if matches(Host(link), domain) {
+			evidence = append(evidence, "profile links to "+link)
+		}
+	}
+	for _, h := range hostRe.FindAllString(p.Bio, -1) {
+		if matches(Host(h), domain) {
+			evidence = append(evidence, "bio mentions "+h)
+			break
+		}
+	}
+	return evidence, attested
+}
+
+// hostRe finds host names in free text, including the domain of an email
+var hostRe = regexp.MustCompile(`(?i)[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]*[a-z0-9])?)+`)
+
+// Host returns the lowercase host of a URL or bare host name, without a
+// leading www.
+func Host(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// matches reports whether host is the domain or one of its subdomains
+func matches(host, domain string) bool {
+	return host != "" && (host == domain || strings.HasSuffix(host, "."+domain))
+}
+
+func orDomain(page, domain string) string {
+	if page == "" {
+		return domain
+	}
+	return page
+}
@@ -0,0 +1,82 @@
+package ownership
+
+import (
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestVerify(t *testing.T) {
+	linked := []models.Source{{Platform: "Twitter", URL: "https://x.com/acme", Page: "https://acme.example/"}}
+	account := func(p *models.Profile) []models.Finding {
+		return []models.Finding{{PluginName: "Twitter", Status: models.StatusExists, Profile: p}}
+	}
+
+	tests := []struct {
+		name     string
+		id       models.Identity
+		want     models.Ownership
+		evidence int
+	}{
+		{
+			name:     "Links back from the website field",
+			id:       models.Identity{Sources: linked, Findings: account(&models.Profile{Links: []string{"https://www.acme.example/about"}})},
+			want:     models.OwnershipVerified,
+			evidence: 2,
+		},
+		{
+			name:     "Bio mentions a subdomain",
+			id:       models.Identity{Sources: linked, Findings: account(&models.Profile{Bio: "Help: support.acme.example or hello@acme.example"})},
+			want:     models.OwnershipVerified,
+			evidence: 2,
+		},
+		{
+			name: "Platform verified domain",
+			id: models.Identity{Findings: []models.Finding{{
+				PluginName: "GitHub",
+				Status:     models.StatusExists,
+				Profile:    &models.Profile{VerifiedDomains: []string{"acme.example"}},
+			}}},
+			want:     models.OwnershipVerified,
+			evidence: 1,
+		},
+		{
+			name:     "Linked but silent",
+			id:       models.Identity{Sources: linked, Findings: account(&models.Profile{Links: []string{"https://acme.example.evil.io"}, Bio: "acme.example.evil.io"})},
+			want:     models.OwnershipOneWay,
+			evidence: 1,
+		},
+		{
+			name:     "Claims the domain unlinked",
+			id:       models.Identity{Findings: account(&models.Profile{Links: []string{"acme.example"}})},
+			want:     models.OwnershipOneWay,
+			evidence: 1,
+		},
+		{
+			name: "Unrelated",
+			id:   models.Identity{Findings: account(nil)},
+			want: models.OwnershipUnrelated,
+		},
+		{
+			name: "No account",
+			id:   models.Identity{Sources: linked, Findings: []models.Finding{{PluginName: "Twitter", Status: models.StatusAvailable}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.id
+			Verify("https://www.acme.example/", &id)
+			if id.Ownership != tt.want {
+				t.Fatalf("Verify() ownership = %q, want %q", id.Ownership, tt.want)
+			}
+			if tt.want == "" {
+				return
+			}
+			evidence, _ := id.Findings[0].Metadata["ownership_evidence"].([]string)
+			if len(evidence) != tt.evidence {
+				t.Errorf("Verify() evidence = %q, want %d entries", evidence, tt.evidence)
+			}
+		})
+	}
+}
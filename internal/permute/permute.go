@@ -0,0 +1,190 @@
+// Package permute generates lookalike handles of a brand name, the handles
+// an impersonator would register to pass as the brand, so they can be
+// checked for existing accounts.
+package permute
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ismailtsdln/socialrecon/internal/confusables"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// Indicator identifies findings about a lookalike handle
+const Indicator = "impersonation_candidate"
+
+// Transformations that produce candidates
+const (
+	Separator         = "separator"          // words joined differently: acme_corp, acme.corp
+	Affix             = "affix"              // acmeofficial, real_acme
+	Omission          = "omission"           // a character dropped: acm
+	Duplication       = "duplication"        // a character doubled: accme
+	Transposition     = "transposition"      // adjacent characters swapped: amce
+	DigitSubstitution = "digit_substitution" // letters replaced by lookalike digits: acm3
+	TLDSuffix         = "tld_suffix"         // a domain ending appended: acmeio, acme_com
+)
+
+// DefaultMax is the number of candidates generated per transformation when
+// no limit is given
+const DefaultMax = 50
+
+// Candidate is a lookalike handle and how it was derived from the brand
+type Candidate struct {
+	Username  string
+	Transform string // one of the transformation constants
+	Detail    string // e.g. "suffix 'official'"
+}
+
+var (
+	// prefixes and suffixes impersonators add to pass as an official account
+	prefixes = []string{"official", "real", "the", "team"}
+	suffixes = []string{"official", "hq", "support", "real", "help", "team", "app"}
+
+	// tlds are domain endings appended to the brand
+	tlds = []string{"com", "net", "org", "io", "co", "app"}
+
+	// joins are the separators handles commonly allow
+	joins = []string{"", "_", "."}
+
+	// digits maps letters to the digits they resemble
+	digits = map[rune]rune{'o': '0', 'i': '1', 'l': '1', 'e': '3', 'a': '4', 's': '5', 't': '7', 'b': '8', 'g': '9'}
+)
+
+// Generate returns up to max lookalike handles of brand per transformation,
+// 0 meaning DefaultMax, so every kind of lookalike is checked. The most
+// plausible impersonations come first: affixes and separators, then domain
+// endings, digit substitutions and typos. The brand itself is never a
+// candidate.
+func Generate(brand string, max int) []Candidate {
+	if max <= 0 {
+		max = DefaultMax
+	}
+	words := split(brand)
+	if len(words) == 0 {
+		return nil
+	}
+	base := strings.Join(words, "")
+
+	var list []Candidate
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(brand)): true}
+	count := make(map[string]int)
+	add := func(username, transform, detail string) {
+		if seen[username] || count[transform] >= max {
+			return
+		}
+		seen[username] = true
+		count[transform]++
+		list = append(list, Candidate{Username: username, Transform: transform, Detail: detail})
+	}
+
+	if len(words) > 1 {
+		add(base, Separator, "words joined")
+		for _, j := range []string{"_", ".", "-"} {
+			add(strings.Join(words, j), Separator, fmt.Sprintf("words joined with '%s'", j))
+		}
+	}
+	for _, s := range suffixes {
+		for _, j := range joins {
+			add(base+j+s, Affix, fmt.Sprintf("suffix '%s'", s))
+		}
+	}
+	for _, p := range prefixes {
+		for _, j := range joins {
+			add(p+j+base, Affix, fmt.Sprintf("prefix '%s'", p))
+		}
+	}
+	for _, t := range tlds {
+		for _, j := range joins {
+			add(base+j+t, TLDSuffix, fmt.Sprintf("ending '%s'", t))
+		}
+	}
+
+	r := []rune(base)
+	subs := 0
+	for i, c := range r {
+		if d, ok := digits[c]; ok {
+			add(replace(r, i, d), DigitSubstitution, fmt.Sprintf("'%c' as '%c'", c, d))
+			subs++
+		}
+	}
+	if subs > 1 {
+		sub := make([]rune, len(r))
+		for i, c := range r {
+			sub[i] = c
+			if d, ok := digits[c]; ok {
+				sub[i] = d
+			}
+		}
+		add(string(sub), DigitSubstitution, "every lookalike letter as a digit")
+	}
+
+	if len(r) > 2 {
+		for i := range r {
+			add(string(r[:i])+string(r[i+1:]), Omission, fmt.Sprintf("'%c' dropped", r[i]))
+		}
+	}
+	for i := 0; i+1 < len(r); i++ {
+		if r[i] != r[i+1] {
+			add(string(r[:i])+string(r[i+1])+string(r[i])+string(r[i+2:]), Transposition, fmt.Sprintf("'%c%c' swapped", r[i], r[i+1]))
+		}
+	}
+	for i := range r {
+		add(string(r[:i+1])+string(r[i:]), Duplication, fmt.Sprintf("'%c' doubled", r[i]))
+	}
+
+	return list
+}
+
+// Tag marks the findings of a lookalike identity as impersonation candidates,
+// or homoglyph impersonations for homoglyph variants, keeping the platform's
+// own indicator in the metadata
+func Tag(id *models.Identity) {
+	p := id.Permutation
+	if p == nil {
+		return
+	}
+	for i := range id.Findings {
+		f := &id.Findings[i]
+		f.SetMeta("profile_indicator", f.Indicator)
+		f.SetMeta("impersonation_of", p.Of)
+		f.SetMeta("transformation", p.Transform)
+		f.SetMeta("transformation_detail", p.Detail)
+		f.Indicator = Indicator
+		if p.Transform == confusables.Transform {
+			f.SetMeta("skeleton", confusables.Skeleton(id.Username))
+			f.Indicator = confusables.Indicator
+		}
+		f.Description = fmt.Sprintf("%s (lookalike of '%s': %s)", f.Description, p.Of, p.Detail)
+	}
+}
+
+// split lowercases a brand name and breaks it into words at separators and
+// spaces, dropping characters no handle can hold
+func split(brand string) []string {
+	var words []string
+	var word strings.Builder
+	for _, c := range strings.ToLower(brand) {
+		switch {
+		case c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)):
+			word.WriteRune(c)
+		case unicode.IsSpace(c) || strings.ContainsRune("_.-", c):
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+func replace(r []rune, i int, c rune) string {
+	out := make([]rune, len(r))
+	copy(out, r)
+	out[i] = c
+	return string(out)
+}
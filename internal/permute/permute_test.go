@@ -0,0 +1,146 @@
+package permute
+
+import (
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/confusables"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name  string
+		brand string
+		want  map[string]string // candidate -> transformation
+		never []string
+	}{
+		{
+			name:  "Single word",
+			brand: "Acme",
+			want: map[string]string{
+				"acmeofficial": Affix,
+				"acme_support": Affix,
+				"real_acme":    Affix,
+				"acmehq":       Affix,
+				"acme.io":      TLDSuffix,
+				"acme_com":     TLDSuffix,
+				"4cme":         DigitSubstitution,
+				"acm3":         DigitSubstitution,
+				"4cm3":         DigitSubstitution,
+				"acm":          Omission,
+				"amce":         Transposition,
+				"accme":        Duplication,
+			},
+			never: []string{"acme", "Acme"},
+		},
+		{
+			name:  "Separated words",
+			brand: "acme_corp",
+			want: map[string]string{
+				"acmecorp":  Separator,
+				"acme.corp": Separator,
+				"acme-corp": Separator,
+			},
+			never: []string{"acme_corp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for _, c := range Generate(tt.brand, 1000) {
+				if _, dup := got[c.Username]; dup {
+					t.Errorf("Generate() returned %q twice", c.Username)
+				}
+				if c.Detail == "" {
+					t.Errorf("Generate() returned %q without detail", c.Username)
+				}
+				got[c.Username] = c.Transform
+			}
+			for username, transform := range tt.want {
+				if got[username] != transform {
+					t.Errorf("candidate %q transform = %q, want %q", username, got[username], transform)
+				}
+			}
+			for _, username := range tt.never {
+				if _, ok := got[username]; ok {
+					t.Errorf("Generate() returned the brand itself as %q", username)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_Limit(t *testing.T) {
+	kinds := func(list []Candidate) map[string]int {
+		n := make(map[string]int)
+		for _, c := range list {
+			n[c.Transform]++
+		}
+		return n
+	}
+
+	for _, kind := range []string{Affix, TLDSuffix, DigitSubstitution, Omission, Transposition, Duplication} {
+		if kinds(Generate("acme", 0))[kind] == 0 {
+			t.Errorf("Generate(max 0) has no %s candidate", kind)
+		}
+	}
+	if n := kinds(Generate("acme corp", 0))[Separator]; n == 0 {
+		t.Errorf("Generate(max 0) has no %s candidate for a multi-word brand", Separator)
+	}
+
+	got := Generate("acme", 3)
+	for kind, n := range kinds(got) {
+		if n > 3 {
+			t.Errorf("Generate(max 3) returned %d %s candidates, want at most 3", n, kind)
+		}
+	}
+	if got[0].Transform != Affix {
+		t.Errorf("first candidate transform = %q, want the most plausible %q", got[0].Transform, Affix)
+	}
+	if got := Generate("!!!", 10); got != nil {
+		t.Errorf("Generate(%q) = %v, want none", "!!!", got)
+	}
+}
+
+func TestTag(t *testing.T) {
+	shared := map[string]interface{}{"url": "https://twitter.com/acmehq"}
+	id := models.Identity{
+		Username:    "acmehq",
+		Permutation: &models.Permutation{Of: "acme", Transform: Affix, Detail: "suffix 'hq'"},
+		Findings: []models.Finding{
+			{PluginName: "Twitter", Indicator: "twitter_profile", Status: models.StatusExists, Description: "Account exists", Metadata: shared},
+		},
+	}
+
+	Tag(&id)
+
+	f := id.Findings[0]
+	if f.Indicator != Indicator {
+		t.Errorf("Indicator = %q, want %q", f.Indicator, Indicator)
+	}
+	if f.Metadata["profile_indicator"] != "twitter_profile" || f.Metadata["transformation"] != Affix || f.Metadata["impersonation_of"] != "acme" {
+		t.Errorf("Metadata = %v, want the original indicator and the transformation", f.Metadata)
+	}
+	if f.Metadata["url"] != "https://twitter.com/acmehq" {
+		t.Errorf("Metadata lost the check's url: %v", f.Metadata)
+	}
+	if _, ok := shared["transformation"]; ok {
+		t.Error("Tag() modified metadata shared with other findings")
+	}
+}
+
+func TestTag_Homoglyph(t *testing.T) {
+	id := models.Identity{
+		Username:    "асmе",
+		Permutation: &models.Permutation{Of: "acme", Transform: confusables.Transform, Detail: "3 letters as Cyrillic"},
+		Findings:    []models.Finding{{PluginName: "Mastodon", Indicator: "mastodon_profile", Status: models.StatusExists}},
+	}
+
+	Tag(&id)
+
+	f := id.Findings[0]
+	if f.Indicator != confusables.Indicator || f.Metadata["skeleton"] != "acme" {
+		t.Errorf("Tag() = %+v, want a homoglyph finding with the shared skeleton", f)
+	}
+}
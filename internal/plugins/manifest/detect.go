@@ -0,0 +1,467 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"golang.org/x/net/html"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	maxBodySize  = 512 << 10 // default amount of body inspected
+	maxBodyLimit = 4 << 20   // hard cap for per-site max_body
+)
+
+// Signal describes a set of response conditions. Every configured criterion
+// must hold for the signal to match; within a list any entry is sufficient.
+// Substring criteria may reference {username}.
+type Signal struct {
+	Status    []int        `yaml:"status" json:"status"`
+	Body      []string     `yaml:"body" json:"body"`             // substrings of the response body
+	BodyRegex []string     `yaml:"body_regex" json:"body_regex"` // regular expressions over the response body
+	Title     []string     `yaml:"title" json:"title"`           // substrings of the HTML <title>
+	Meta      []MetaMarker `yaml:"meta" json:"meta"`
+	JSON      []JSONMarker `yaml:"json" json:"json"`
+	Canonical []string     `yaml:"canonical" json:"canonical"` // substrings of <link rel="canonical">
+	Redirect  string       `yaml:"redirect" json:"redirect"`   // regular expression over the final URL
+
+	bodyRes    []*regexp.Regexp
+	redirectRe *regexp.Regexp
+}
+
+// MetaMarker matches a <meta> tag by name or property
+type MetaMarker struct {
+	Name     string `yaml:"name" json:"name"`         // matched against both name= and property=
+	Contains string `yaml:"contains" json:"contains"` // empty means the tag only has to be present
+}
+
+// JSONMarker matches a field of a JSON response body. Without Equals or
+// Contains the field only has to be present and non-null.
+type JSONMarker struct {
+	Path     string `yaml:"path" json:"path"` // dotted path, e.g. "data.user.id"
+	Equals   string `yaml:"equals" json:"equals"`
+	Contains string `yaml:"contains" json:"contains"` // case-insensitive substring, may reference {username}
+}
+
+// Signals is a list of alternative signals; the first one that matches wins.
+// A single signal may be written without the surrounding list.
+type Signals []Signal
+
+func (s *Signals) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var sig Signal
+		if err := node.Decode(&sig); err != nil {
+			return err
+		}
+		*s = Signals{sig}
+		return nil
+	}
+	var list []Signal
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+func (s *Signals) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var sig Signal
+		if err := json.Unmarshal(data, &sig); err != nil {
+			return err
+		}
+		*s = Signals{sig}
+		return nil
+	}
+	var list []Signal
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// outcome pairs a status with the signals that establish it
+type outcome struct {
+	status  models.Status
+	signals Signals
+}
+
+// outcomes returns the site's signals in evaluation order. Negative markers
+// are checked before positive ones so soft-404 pages are not reported as
+// existing profiles.
+func (s *Site) outcomes() []outcome {
+	return []outcome{
+		{status: models.StatusSuspended, signals: s.Suspended},
+		{status: models.StatusDeactivated, signals: s.Deactivated},
+		{status: models.StatusRestricted, signals: s.Restricted},
+		{status: models.StatusAvailable, signals: s.Absent},
+		{status: models.StatusPrivate, signals: s.Private},
+		{status: models.StatusExists, signals: s.Exists},
+		{status: models.StatusUnknown, signals: s.Unknown},
+	}
+}
+
+// needsBody reports whether any signal or profile rule of the site inspects
+// the response body
+func (s *Site) needsBody() bool {
+	if s.Profile != nil {
+		return true
+	}
+	for _, o := range s.outcomes() {
+		if o.signals.needsBody() {
+			return true
+		}
+	}
+	return false
+}
+
+// needsBody reports whether any of the signals inspects the response body
+func (s Signals) needsBody() bool {
+	for i := range s {
+		if s[i].needsBody() {
+			return true
+		}
+	}
+	return false
+}
+
+// response is the part of an HTTP response visible to signals
+type response struct {
+	status   int
+	finalURL string
+	body     []byte
+	username string
+
+	doc      *document
+	json     interface{}
+	jsonDone bool
+}
+
+// verdict is the result of evaluating a site's signals
+type verdict struct {
+	status   models.Status
+	evidence []string
+}
+
+// detect evaluates all outcomes in order and returns the first match
+func (s *Site) detect(r *response) (verdict, bool) {
+	for _, o := range s.outcomes() {
+		for i := range o.signals {
+			if evidence, ok := o.signals[i].match(r); ok {
+				return verdict{status: o.status, evidence: evidence}, true
+			}
+		}
+	}
+	return verdict{}, false
+}
+
+func (s Signals) compile() error {
+	for i := range s {
+		if err := s[i].compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sig *Signal) compile() error {
+	if sig.empty() {
+		return fmt.Errorf("empty signal")
+	}
+	for _, expr := range sig.BodyRegex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid body_regex %q: %w", expr, err)
+		}
+		sig.bodyRes = append(sig.bodyRes, re)
+	}
+	if sig.Redirect != "" {
+		re, err := regexp.Compile(sig.Redirect)
+		if err != nil {
+			return fmt.Errorf("invalid redirect %q: %w", sig.Redirect, err)
+		}
+		sig.redirectRe = re
+	}
+	for _, m := range sig.Meta {
+		if m.Name == "" {
+			return fmt.Errorf("meta marker without a name")
+		}
+	}
+	for _, m := range sig.JSON {
+		if m.Path == "" {
+			return fmt.Errorf("json marker without a path")
+		}
+	}
+	return nil
+}
+
+func (sig *Signal) empty() bool {
+	return len(sig.Status) == 0 && !sig.needsBody() && sig.Redirect == ""
+}
+
+// needsBody reports whether the signal inspects the response body
+func (sig *Signal) needsBody() bool {
+	return len(sig.Body) > 0 || len(sig.BodyRegex) > 0 || len(sig.Title) > 0 ||
+		len(sig.Meta) > 0 || len(sig.JSON) > 0 || len(sig.Canonical) > 0
+}
+
+// match evaluates the signal against a response and returns the evidence
+// that satisfied each criterion
+func (sig *Signal) match(r *response) ([]string, bool) {
+	var evidence []string
+
+	if len(sig.Status) > 0 {
+		found := false
+		for _, code := range sig.Status {
+			if code == r.status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("status %d", r.status))
+	}
+
+	if len(sig.Body) > 0 {
+		sub, ok := containsAny(string(r.body), sig.Body, r.username)
+		if !ok {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("body contains %q", sub))
+	}
+
+	if len(sig.bodyRes) > 0 {
+		found := false
+		for _, re := range sig.bodyRes {
+			if m := re.Find(r.body); m != nil {
+				evidence = append(evidence, fmt.Sprintf("body matches %q", truncate(string(m), 120)))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	if len(sig.Title) > 0 {
+		title := r.document().title
+		sub, ok := containsAny(title, sig.Title, r.username)
+		if !ok {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("title %q contains %q", truncate(title, 120), sub))
+	}
+
+	if len(sig.Meta) > 0 {
+		e, ok := r.document().matchMeta(sig.Meta, r.username)
+		if !ok {
+			return nil, false
+		}
+		evidence = append(evidence, e)
+	}
+
+	if len(sig.JSON) > 0 {
+		e, ok := r.matchJSON(sig.JSON, r.username)
+		if !ok {
+			return nil, false
+		}
+		evidence = append(evidence, e)
+	}
+
+	if len(sig.Canonical) > 0 {
+		canonical := r.document().canonical
+		sub, ok := containsAny(canonical, sig.Canonical, r.username)
+		if canonical == "" || !ok {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("canonical %q contains %q", canonical, sub))
+	}
+
+	if sig.redirectRe != nil {
+		if !sig.redirectRe.MatchString(r.finalURL) {
+			return nil, false
+		}
+		evidence = append(evidence, fmt.Sprintf("final URL %s", r.finalURL))
+	}
+
+	return evidence, true
+}
+
+// containsAny reports the first candidate (with {username} expanded) found
+// in s, compared case-insensitively
+func containsAny(s string, candidates []string, username string) (string, bool) {
+	lower := strings.ToLower(s)
+	for _, c := range candidates {
+		c = strings.ReplaceAll(c, usernamePlaceholder, username)
+		if strings.Contains(lower, strings.ToLower(c)) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// truncate shortens s to n characters, never splitting one
+func truncate(s string, n int) string {
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos] + "..."
+		}
+		i++
+	}
+	return s
+}
+
+// document holds the parts of an HTML page inspected by signals
+type document struct {
+	title     string
+	canonical string
+	meta      map[string][]string // lower-cased name/property -> contents
+}
+
+func (r *response) document() *document {
+	if r.doc == nil {
+		r.doc = parseDocument(r.body)
+	}
+	return r.doc
+}
+
+func parseDocument(body []byte) *document {
+	doc := &document{meta: make(map[string][]string)}
+	z := html.NewTokenizer(bytes.NewReader(body))
+	inTitle := false
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return doc
+		case html.TextToken:
+			if inTitle && doc.title == "" {
+				doc.title = strings.TrimSpace(html.UnescapeString(string(z.Text())))
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "title" {
+				inTitle = false
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "title":
+				inTitle = tt == html.StartTagToken
+			case "meta":
+				var key, content string
+				for _, a := range t.Attr {
+					switch a.Key {
+					case "name", "property":
+						key = strings.ToLower(a.Val)
+					case "content":
+						content = a.Val
+					}
+				}
+				if key != "" {
+					doc.meta[key] = append(doc.meta[key], content)
+				}
+			case "link":
+				var rel, href string
+				for _, a := range t.Attr {
+					switch a.Key {
+					case "rel":
+						rel = strings.ToLower(a.Val)
+					case "href":
+						href = a.Val
+					}
+				}
+				if rel == "canonical" && doc.canonical == "" {
+					doc.canonical = href
+				}
+			}
+		}
+	}
+}
+
+func (d *document) matchMeta(markers []MetaMarker, username string) (string, bool) {
+	for _, m := range markers {
+		for _, content := range d.meta[strings.ToLower(m.Name)] {
+			if m.Contains == "" {
+				return fmt.Sprintf("meta %s present", m.Name), true
+			}
+			if sub, ok := containsAny(content, []string{m.Contains}, username); ok {
+				return fmt.Sprintf("meta %s %q contains %q", m.Name, truncate(content, 120), sub), true
+			}
+		}
+	}
+	return "", false
+}
+
+// decodeJSON returns the decoded JSON body, or nil if it is not JSON
+func (r *response) decodeJSON() interface{} {
+	if !r.jsonDone {
+		r.jsonDone = true
+		if err := json.Unmarshal(r.body, &r.json); err != nil {
+			r.json = nil
+		}
+	}
+	return r.json
+}
+
+func (r *response) matchJSON(markers []JSONMarker, username string) (string, bool) {
+	doc := r.decodeJSON()
+	if doc == nil {
+		return "", false
+	}
+
+	for _, m := range markers {
+		v, ok := lookupJSON(doc, m.Path)
+		if !ok || v == nil {
+			continue
+		}
+		switch {
+		case m.Equals != "":
+			if fmt.Sprint(v) == m.Equals {
+				return fmt.Sprintf("json %s = %s", m.Path, m.Equals), true
+			}
+		case m.Contains != "":
+			if sub, ok := containsAny(fmt.Sprint(v), []string{m.Contains}, username); ok {
+				return fmt.Sprintf("json %s %q contains %q", m.Path, truncate(fmt.Sprint(v), 120), sub), true
+			}
+		default:
+			return fmt.Sprintf("json %s present", m.Path), true
+		}
+	}
+	return "", false
+}
+
+// lookupJSON resolves a dotted path; numeric segments index into arrays
+func lookupJSON(v interface{}, path string) (interface{}, bool) {
+	for _, part := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
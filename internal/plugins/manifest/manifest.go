@@ -0,0 +1,270 @@
+package manifest
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/scanner"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed sites.yaml
+var defaultSites []byte
+
+// Placeholder substituted with the (escaped) username in URL templates
+const usernamePlaceholder = "{username}"
+
+// Site is a declarative definition of a single platform check
+type Site struct {
+	Name            string        `yaml:"name" json:"name"`
+	Description     string        `yaml:"description" json:"description"`
+	Indicator       string        `yaml:"indicator" json:"indicator"`               // e.g., "github_profile"
+	URL             string        `yaml:"url" json:"url"`                           // e.g., "https://github.com/{username}"
+	ProfileURL      string        `yaml:"profile_url" json:"profile_url"`           // human-facing URL when URL is an API endpoint
+	UsernamePattern string        `yaml:"username_pattern" json:"username_pattern"` // handles not matching are skipped
+	Reserved        []string      `yaml:"reserved" json:"reserved"`                 // platform paths that are not profiles
+	MaxBody         int64         `yaml:"max_body" json:"max_body"`                 // bytes of body inspected, defaults to maxBodySize
+	RateLimit       float64       `yaml:"rate_limit" json:"rate_limit"`             // requests per second to the probe host
+	Request         Request       `yaml:"request" json:"request"`
+	Exists          Signals       `yaml:"exists" json:"exists"`
+	Absent          Signals       `yaml:"absent" json:"absent"`
+	Suspended       Signals       `yaml:"suspended" json:"suspended"`
+	Deactivated     Signals       `yaml:"deactivated" json:"deactivated"`
+	Private         Signals       `yaml:"private" json:"private"`
+	Restricted      Signals       `yaml:"restricted" json:"restricted"`
+	Confirm         *Confirm      `yaml:"confirm" json:"confirm"`
+	Unknown         Signals       `yaml:"unknown" json:"unknown"` // login walls, interstitials
+	Profile         *ProfileRules `yaml:"profile" json:"profile"` // account details read from the probe response
+
+	usernameRe *regexp.Regexp
+	reserved   map[string]bool
+	platform   *scanner.Platform // handle model of a built-in platform
+}
+
+// Confirm is a secondary probe, typically a registration availability
+// endpoint, run when the profile looks absent. Platforms hide suspended and
+// flagged accounts behind the same 404 as free handles; if the handle is
+// still taken the finding is reported with Status instead of available.
+type Confirm struct {
+	URL     string        `yaml:"url" json:"url"`
+	Request Request       `yaml:"request" json:"request"`
+	Taken   Signals       `yaml:"taken" json:"taken"`
+	Status  models.Status `yaml:"status" json:"status"` // defaults to suspended
+}
+
+// Request describes how the probe request is built
+type Request struct {
+	Method  string            `yaml:"method" json:"method"`
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	Body    string            `yaml:"body" json:"body"`
+}
+
+// Default returns the site definitions embedded in the binary
+func Default() ([]Site, error) {
+	return Parse(defaultSites, "yaml")
+}
+
+// LoadFile reads site definitions from a YAML or JSON manifest
+func LoadFile(path string) ([]Site, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "json"
+	}
+
+	sites, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sites, nil
+}
+
+// Parse decodes and validates a manifest. A manifest is either a list of
+// sites or a document with a top-level "sites" key.
+func Parse(data []byte, format string) ([]Site, error) {
+	var sites []Site
+
+	switch format {
+	case "json":
+		data = bytes.TrimSpace(data)
+		if len(data) > 0 && data[0] == '{' {
+			var doc struct {
+				Sites []Site `json:"sites"`
+			}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				return nil, err
+			}
+			sites = doc.Sites
+		} else if err := json.Unmarshal(data, &sites); err != nil {
+			return nil, err
+		}
+	case "yaml":
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		if len(node.Content) == 0 {
+			break
+		}
+		root := node.Content[0]
+		if root.Kind == yaml.MappingNode {
+			var doc struct {
+				Sites []Site `yaml:"sites"`
+			}
+			if err := root.Decode(&doc); err != nil {
+				return nil, err
+			}
+			sites = doc.Sites
+		} else if err := root.Decode(&sites); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s", format)
+	}
+
+	for i := range sites {
+		if err := sites[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return sites, nil
+}
+
+// Merge overlays user-supplied sites on top of base. Sites are matched by
+// name (case-insensitive); unmatched overrides are appended.
+func Merge(base []Site, overrides ...[]Site) []Site {
+	merged := append([]Site(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, s := range merged {
+		index[strings.ToLower(s.Name)] = i
+	}
+
+	for _, list := range overrides {
+		for _, s := range list {
+			key := strings.ToLower(s.Name)
+			if i, ok := index[key]; ok {
+				merged[i] = s
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, s)
+		}
+	}
+
+	return merged
+}
+
+// handle returns the handle to check for target. A profile link on any
+// alias of the site's platform, such as x.com/acme for Twitter, is reduced to
+// its handle; anything else is checked as given.
+func (s *Site) handle(target string) string {
+	if s.platform == nil || !strings.ContainsAny(target, "./") {
+		return target
+	}
+	if id, ok := scanner.Resolve(target); ok && id.Platform == s.platform.Name {
+		return id.Username
+	}
+	return target
+}
+
+// validate returns a *scanner.HandleError if username cannot be a profile on
+// the site. A username_pattern replaces the built-in platform's handle rules;
+// reserved paths of both apply.
+func (s *Site) validate(username string) error {
+	if s.reserved[strings.ToLower(username)] {
+		return &scanner.HandleError{Platform: s.Name, Handle: username, Reserved: true}
+	}
+	if s.usernameRe != nil {
+		if !s.usernameRe.MatchString(username) {
+			return &scanner.HandleError{Platform: s.Name, Handle: username}
+		}
+		if s.platform != nil && s.platform.Reserved[strings.ToLower(username)] {
+			return &scanner.HandleError{Platform: s.Name, Handle: username, Reserved: true}
+		}
+		return nil
+	}
+	if s.platform != nil {
+		return s.platform.Validate(username)
+	}
+	return nil
+}
+
+func (s *Site) compile() error {
+	if s.Name == "" {
+		return fmt.Errorf("site definition without a name")
+	}
+	if !strings.Contains(s.URL, usernamePlaceholder) {
+		return fmt.Errorf("site %s: url must contain %s", s.Name, usernamePlaceholder)
+	}
+	if s.Indicator == "" {
+		s.Indicator = strings.ToLower(strings.ReplaceAll(s.Name, " ", "_")) + "_profile"
+	}
+	if s.Request.Method == "" {
+		s.Request.Method = "GET"
+	}
+	s.Request.Method = strings.ToUpper(s.Request.Method)
+
+	if s.UsernamePattern != "" {
+		re, err := regexp.Compile(s.UsernamePattern)
+		if err != nil {
+			return fmt.Errorf("site %s: invalid username_pattern: %w", s.Name, err)
+		}
+		s.usernameRe = re
+	}
+	s.platform = scanner.LookupPlatform(s.Name)
+	s.reserved = make(map[string]bool, len(s.Reserved))
+	for _, w := range s.Reserved {
+		s.reserved[strings.ToLower(w)] = true
+	}
+
+	if s.MaxBody <= 0 || s.MaxBody > maxBodyLimit {
+		s.MaxBody = maxBodySize
+	}
+
+	for _, o := range s.outcomes() {
+		if err := o.signals.compile(); err != nil {
+			return fmt.Errorf("site %s: %s: %w", s.Name, o.status, err)
+		}
+	}
+	if s.Profile != nil {
+		if err := s.Profile.compile(); err != nil {
+			return fmt.Errorf("site %s: profile: %w", s.Name, err)
+		}
+	}
+	if c := s.Confirm; c != nil {
+		if !strings.Contains(c.URL, usernamePlaceholder) {
+			return fmt.Errorf("site %s: confirm url must contain %s", s.Name, usernamePlaceholder)
+		}
+		if len(c.Taken) == 0 {
+			return fmt.Errorf("site %s: confirm requires taken signals", s.Name)
+		}
+		if err := c.Taken.compile(); err != nil {
+			return fmt.Errorf("site %s: confirm: %w", s.Name, err)
+		}
+		if c.Request.Method == "" {
+			c.Request.Method = "GET"
+		}
+		c.Request.Method = strings.ToUpper(c.Request.Method)
+		if c.Status == "" {
+			c.Status = models.StatusSuspended
+		}
+	}
+
+	if len(s.Exists) == 0 && len(s.Absent) == 0 {
+		return fmt.Errorf("site %s: at least one of exists/absent must be defined", s.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,532 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestDefault(t *testing.T) {
+	sites, err := Default()
+	if err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+
+	want := map[string]string{
+		"GitHub":    "github_profile",
+		"Twitter":   "twitter_profile",
+		"Instagram": "instagram_profile",
+	}
+	for _, s := range sites {
+		if indicator, ok := want[s.Name]; ok && s.Indicator != indicator {
+			t.Errorf("site %s indicator = %q, want %q", s.Name, s.Indicator, indicator)
+		}
+		delete(want, s.Name)
+	}
+	for name := range want {
+		t.Errorf("embedded manifest is missing %s", name)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Missing placeholder", data: `[{"name": "X", "url": "https://x.test/", "exists": {"status": [200]}}]`},
+		{name: "No signals", data: `[{"name": "X", "url": "https://x.test/{username}"}]`},
+		{name: "Bad regex", data: `[{"name": "X", "url": "https://x.test/{username}", "exists": {"body_regex": ["("]}}]`},
+		{name: "Profile rule without source", data: `[{"name": "X", "url": "https://x.test/{username}", "exists": {"status": [200]}, "profile": {"bio": {"regex": "."}}}]`},
+		{name: "Profile rule with two sources", data: `[{"name": "X", "url": "https://x.test/{username}", "exists": {"status": [200]}, "profile": {"bio": {"meta": "description", "json": "bio"}}}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data), "json"); err == nil {
+				t.Errorf("Parse() expected error")
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := []Site{{Name: "GitHub", URL: "a"}, {Name: "Twitter", URL: "b"}}
+	merged := Merge(base, []Site{{Name: "github", URL: "c"}, {Name: "GitLab", URL: "d"}})
+
+	if len(merged) != 3 {
+		t.Fatalf("Merge() returned %d sites, want 3", len(merged))
+	}
+	if merged[0].URL != "c" {
+		t.Errorf("override not applied, URL = %q", merged[0].URL)
+	}
+	if merged[2].Name != "GitLab" {
+		t.Errorf("new site not appended, got %q", merged[2].Name)
+	}
+}
+
+func TestSitePlugin_Check(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/taken":
+			w.Write([]byte("<title>taken on Example</title>"))
+		case "/soft404":
+			w.Write([]byte("<title>Page not found</title>"))
+		case "/blocked":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	sites, err := Parse([]byte(`
+- name: Example
+  url: `+srv.URL+`/{username}
+  username_pattern: '^[a-z0-9]+$'
+  reserved: [Admin]
+  exists:
+    status: [200]
+  absent:
+    body: ["Page not found"]
+`), "yaml")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	p := NewPlugin(sites[0])
+	p.SetClient(httpx.NewClient(time.Second, nil, httpx.RetryPolicy{MaxAttempts: 1}))
+
+	tests := []struct {
+		target  string
+		status  models.Status
+		wantErr bool
+	}{
+		{target: "taken", status: "exists"},
+		{target: "soft404", status: "available"},
+		{target: "blocked", wantErr: true},
+		{target: "Not-Valid", status: models.StatusInvalidUsername},
+		{target: "admin", status: models.StatusInvalidUsername},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			findings, err := p.Check(context.Background(), tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.status == "" {
+				if len(findings) != 0 {
+					t.Errorf("Check() = %v, want no findings", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Status != tt.status {
+				t.Errorf("Check() = %v, want status %q", findings, tt.status)
+			}
+			if findings[0].Indicator != "example_profile" {
+				t.Errorf("Indicator = %q, want example_profile", findings[0].Indicator)
+			}
+		})
+	}
+}
+
+func TestSitePlugin_CheckBuiltinHandles(t *testing.T) {
+	sites, err := Default()
+	if err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	byName := make(map[string]*SitePlugin)
+	for _, p := range NewPlugins(sites) {
+		sp := p.(*SitePlugin)
+		byName[sp.Name()] = sp
+	}
+
+	tests := []struct {
+		site   string
+		target string
+	}{
+		{site: "GitHub", target: "features"},
+		{site: "GitHub", target: "acme--inc"},
+		{site: "GitHub", target: "-acme"},
+		{site: "Twitter", target: "share"},
+		{site: "Twitter", target: "Intent"},
+		{site: "Twitter", target: "https://x.com/intent/tweet"},
+		{site: "Twitter", target: "sixteen_chars_xx"},
+		{site: "Instagram", target: "p"},
+		{site: "Instagram", target: "acme."},
+	}
+
+	// No request may be made; any attempt fails the test with an error
+	client := httpx.NewClient(time.Millisecond, nil, httpx.RetryPolicy{MaxAttempts: 1})
+	for _, tt := range tests {
+		t.Run(tt.site+"/"+tt.target, func(t *testing.T) {
+			p := byName[tt.site]
+			p.SetClient(client)
+			findings, err := p.Check(context.Background(), tt.target)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if len(findings) != 1 || findings[0].Status != models.StatusInvalidUsername {
+				t.Errorf("Check() = %v, want invalid_username", findings)
+			}
+		})
+	}
+}
+
+func TestSite_Detect(t *testing.T) {
+	sites, err := Parse([]byte(`
+- name: Example
+  url: https://example.test/{username}
+  suspended:
+    json: [{path: user.state, equals: suspended}]
+  absent:
+    - status: [404]
+    - title: ["Page not found"]
+  exists:
+    - meta: [{name: og:title, contains: "(@{username})"}]
+    - json: [{path: user.id}]
+  unknown:
+    redirect: '/login$'
+`), "yaml")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	site := sites[0]
+
+	tests := []struct {
+		name     string
+		resp     response
+		status   models.Status
+		evidence string
+	}{
+		{
+			name:     "Meta tag",
+			resp:     response{status: 200, body: []byte(`<meta property="og:title" content="Acme (@acme) on Example">`)},
+			status:   "exists",
+			evidence: `meta og:title "Acme (@acme) on Example" contains "(@acme)"`,
+		},
+		{
+			name:   "Meta tag for another user",
+			resp:   response{status: 200, body: []byte(`<meta property="og:title" content="Other (@other)">`)},
+			status: "",
+		},
+		{
+			name:     "Soft 404",
+			resp:     response{status: 200, body: []byte(`<html><head><title>Page not found &middot; Example</title></head></html>`)},
+			status:   "available",
+			evidence: `title "Page not found · Example" contains "Page not found"`,
+		},
+		{
+			name:     "JSON suspended",
+			resp:     response{status: 200, body: []byte(`{"user": {"id": 1, "state": "suspended"}}`)},
+			status:   "suspended",
+			evidence: "json user.state = suspended",
+		},
+		{
+			name:     "JSON exists",
+			resp:     response{status: 200, body: []byte(`{"user": {"id": 1, "state": "active"}}`)},
+			status:   "exists",
+			evidence: "json user.id present",
+		},
+		{
+			name:     "Login wall",
+			resp:     response{status: 200, finalURL: "https://example.test/login"},
+			status:   "unknown",
+			evidence: "final URL https://example.test/login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.resp.username = "acme"
+			v, ok := site.detect(&tt.resp)
+			if !ok {
+				if tt.status != "" {
+					t.Fatalf("detect() matched nothing, want %q", tt.status)
+				}
+				return
+			}
+			if v.status != tt.status {
+				t.Errorf("detect() status = %q, want %q", v.status, tt.status)
+			}
+			if len(v.evidence) == 0 || v.evidence[0] != tt.evidence {
+				t.Errorf("detect() evidence = %q, want %q", v.evidence, tt.evidence)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{s: "acme", n: 10, want: "acme"},
+		{s: "acme", n: 4, want: "acme"},
+		{s: "acme inc", n: 4, want: "acme..."},
+		{s: "Café Acme", n: 4, want: "Café..."},
+		{s: "Ａｃｍｅ", n: 2, want: "Ａｃ..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got := truncate(tt.s, tt.n)
+			if got != tt.want || !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSitePlugin_CheckConfirm(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/signup" {
+			switch r.URL.Query().Get("value") {
+			case "hidden":
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte("Username hidden is not available."))
+			case "throttled":
+				w.WriteHeader(http.StatusTooManyRequests)
+			case "broken":
+				w.WriteHeader(http.StatusBadGateway)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	sites, err := Parse([]byte(`
+- name: Example
+  url: `+srv.URL+`/{username}
+  exists: {status: [200]}
+  absent: {status: [404]}
+  confirm:
+    url: `+srv.URL+`/signup?value={username}
+    taken: {status: [422], body: ["not available"]}
+`), "yaml")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	p := NewPlugin(sites[0])
+	p.SetClient(httpx.NewClient(time.Second, nil, httpx.RetryPolicy{MaxAttempts: 1}))
+
+	tests := []struct {
+		target  string
+		status  models.Status
+		wantErr bool
+	}{
+		{target: "free", status: models.StatusAvailable},
+		{target: "hidden", status: models.StatusSuspended},
+		{target: "throttled", wantErr: true},
+		{target: "broken", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			findings, err := p.Check(context.Background(), tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(findings) != 1 || findings[0].Status != tt.status {
+				t.Errorf("Check() = %v, want status %q", findings, tt.status)
+			}
+			if attempts := findings[0].Metadata["attempts"]; attempts != 2 {
+				t.Errorf("attempts = %v, want 2 (probe and confirmation)", attempts)
+			}
+		})
+	}
+}
+
+func TestSitePlugin_CheckProfile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/acme":
+			w.Write([]byte(`<html><head>
+<meta property="og:title" content="Acme Inc (@acme)">
+<meta property="og:description" content="12.5K Followers, 300 Following, 1,234 Posts - Widgets &amp; more">
+<meta property="og:image" content="https://cdn.example/acme.png">
+</head><body>
+<script>{"created_at":"Wed Mar 04 10:00:00 +0000 2015","verified":true,"external_url":"https:\/\/acme.example\/about","location":"Caf\u00e9 \"Acme\" \/ HQ"}</script>
+<relative-time datetime="2024-01-10T08:00:00Z"></relative-time><relative-time datetime="2024-06-02T09:30:00Z"></relative-time><relative-time datetime="2023-11-20T00:00:00Z"></relative-time>
+<a rel="me" href="https://acme.example">site</a><a rel="me" href="https://shop.acme.example">shop</a>
+</body></html>`))
+		case "/api/acme":
+			w.Write([]byte(`{"user": {"name": "Acme", "followers": 42, "joined": 1425463200, "links": ["https://acme.example"], "domains": ["acme.example", "acme.io"]}}`))
+		default:
+			w.Write([]byte(`<html><head><title>Nothing here</title></head></html>`))
+		}
+	}))
+	defer srv.Close()
+
+	sites, err := Parse([]byte(`
+- name: HTML
+  url: `+srv.URL+`/{username}
+  exists: {status: [200]}
+  profile:
+    display_name: {meta: og:title, regex: '^(.+?) \(@'}
+    bio: {meta: og:description, regex: ' - (.+)$'}
+    avatar: {meta: og:image}
+    followers: {meta: og:description, regex: '([0-9.,]+[KMB]?) Followers'}
+    following: {meta: og:description, regex: '([0-9.,]+[KMB]?) Following'}
+    posts: {meta: og:description, regex: '([0-9.,]+[KMB]?) Posts'}
+    created: {body_regex: '"created_at":"([^"]+)"'}
+    last_active: {body_regex: 'datetime="([^"]+)"'}
+    verified: {body_regex: '"verified":(true|false)'}
+    links: [{body_regex: 'rel="me" href="([^"]+)"'}, {body_regex: '"external_url":"([^"]+)"'}]
+    location: {body_regex: '"location":"((?:[^"\\]|\\.)*)"'}
+- name: API
+  url: `+srv.URL+`/api/{username}
+  exists: {json: [{path: user}]}
+  profile:
+    display_name: [{json: user.display_name}, {json: user.name}]
+    followers: {json: user.followers}
+    created: {json: user.joined}
+    links: {json: user.links}
+    verified_domains: {json: user.domains}
+`), "yaml")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	count := func(n int64) *int64 { return &n }
+	date := func(s string) *time.Time {
+		d, _ := time.Parse(time.RFC3339, s)
+		return &d
+	}
+	tests := []struct {
+		site   int
+		target string
+		want   *models.Profile
+	}{
+		{
+			site:   0,
+			target: "acme",
+			want: &models.Profile{
+				DisplayName: "Acme Inc",
+				Bio:         "Widgets & more",
+				AvatarURL:   "https://cdn.example/acme.png",
+				Followers:   count(12500),
+				Following:   count(300),
+				Posts:       count(1234),
+				CreatedAt:   date("2015-03-04T10:00:00Z"),
+				LastActive:  date("2024-06-02T09:30:00Z"),
+				Verified:    true,
+				Links:       []string{"https://acme.example", "https://shop.acme.example", "https://acme.example/about"},
+				Location:    `Café "Acme" / HQ`,
+			},
+		},
+		{
+			site:   1,
+			target: "acme",
+			want: &models.Profile{
+				DisplayName:     "Acme",
+				Followers:       count(42),
+				CreatedAt:       date("2015-03-04T10:00:00Z"),
+				Links:           []string{"https://acme.example"},
+				VerifiedDomains: []string{"acme.example", "acme.io"},
+			},
+		},
+		{site: 0, target: "blank"},
+	}
+
+	for _, tt := range tests {
+		t.Run(sites[tt.site].Name+"/"+tt.target, func(t *testing.T) {
+			p := NewPlugin(sites[tt.site])
+			p.SetClient(httpx.NewClient(time.Second, nil, httpx.RetryPolicy{MaxAttempts: 1}))
+			findings, err := p.Check(context.Background(), tt.target)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if !reflect.DeepEqual(findings[0].Profile, tt.want) {
+				t.Errorf("Profile = %s, want %s", profileString(findings[0].Profile), profileString(tt.want))
+			}
+		})
+	}
+}
+
+func TestDefault_ProfileLinks(t *testing.T) {
+	sites, err := Default()
+	if err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	byName := make(map[string]*Site)
+	for i := range sites {
+		byName[sites[i].Name] = &sites[i]
+	}
+
+	tests := []struct {
+		site  string
+		body  string
+		links []string
+		bio   string
+	}{
+		{
+			site: "Twitter",
+			body: `{"legacy":{"screen_name":"acme","entities":{"description":{"urls":[{"display_url":"github.com/acme","expanded_url":"https://github.com/acme","indices":[5,28]}]},"url":{"urls":[{"display_url":"acme.example","expanded_url":"https://acme.example","url":"https://t.co/x","indices":[0,23]}]}}},` +
+				`"tweet":{"legacy":{"created_at":"Wed Mar 04 10:00:00 +0000 2015","entities":{"urls":[{"expanded_url":"https://elsewhere.example/post"}]}}}}`,
+			links: []string{"https://acme.example", "https://github.com/acme"},
+		},
+		{
+			site:  "Instagram",
+			body:  `{"user":{"biography":"Say \"hi\"","external_url":"https:\/\/acme.example\/","username":"acme"}}`,
+			links: []string{"https://acme.example/"},
+			bio:   `Say "hi"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.site, func(t *testing.T) {
+			site := byName[tt.site]
+			if site == nil || site.Profile == nil {
+				t.Fatalf("embedded manifest has no profile rules for %s", tt.site)
+			}
+			prof := site.Profile.extract(&response{status: 200, body: []byte(tt.body), username: "acme"})
+			if prof == nil || !reflect.DeepEqual(prof.Links, tt.links) || tt.bio != "" && prof.Bio != tt.bio {
+				t.Errorf("Profile = %s, want links %q and bio %q", profileString(prof), tt.links, tt.bio)
+			}
+		})
+	}
+}
+
+func profileString(p *models.Profile) string {
+	if p == nil {
+		return "nil"
+	}
+	data, _ := json.Marshal(p)
+	return string(data)
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{in: "1234", want: 1234, ok: true},
+		{in: "1,234", want: 1234, ok: true},
+		{in: "12.5K", want: 12500, ok: true},
+		{in: "3M followers", want: 3000000, ok: true},
+		{in: "1,2k", want: 1200, ok: true},
+		{in: "300 Following", want: 300, ok: true},
+		{in: "none"},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseCount(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseCount(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/plugins"
+)
+
+// SitePlugin implements plugins.Plugin for a single manifest entry
+type SitePlugin struct {
+	site   Site
+	client *httpx.Client
+}
+
+// NewPlugin creates a plugin from a site definition
+func NewPlugin(site Site) *SitePlugin {
+	return &SitePlugin{
+		site:   site,
+		client: httpx.NewClient(10*time.Second, nil, httpx.DefaultRetryPolicy()),
+	}
+}
+
+// NewPlugins creates a plugin for every site definition
+func NewPlugins(sites []Site) []plugins.Plugin {
+	list := make([]plugins.Plugin, 0, len(sites))
+	for _, s := range sites {
+		list = append(list, NewPlugin(s))
+	}
+	return list
+}
+
+// LoadPlugins builds plugins from the embedded manifest overlaid with the
+// given user-supplied manifest files
+func LoadPlugins(paths ...string) ([]plugins.Plugin, error) {
+	sites, err := Default()
+	if err != nil {
+		return nil, fmt.Errorf("embedded manifest: %w", err)
+	}
+
+	for _, path := range paths {
+		extra, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sites = Merge(sites, extra)
+	}
+
+	return NewPlugins(sites), nil
+}
+
+func (p *SitePlugin) Name() string {
+	return p.site.Name
+}
+
+func (p *SitePlugin) Description() string {
+	if p.site.Description != "" {
+		return p.site.Description
+	}
+	return fmt.Sprintf("Checks for %s profiles", p.site.Name)
+}
+
+// SetClient routes the plugin's requests through a shared client and
+// registers the site's own rate limit, if any
+func (p *SitePlugin) SetClient(c *httpx.Client) {
+	p.client = c
+	if p.site.RateLimit > 0 {
+		for _, host := range p.Hosts() {
+			c.Limiter().SetRate(host, p.site.RateLimit)
+		}
+	}
+}
+
+// Hosts returns the hosts of the probe and confirmation URLs
+func (p *SitePlugin) Hosts() []string {
+	templates := []string{p.site.URL}
+	if p.site.Confirm != nil {
+		templates = append(templates, p.site.Confirm.URL)
+	}
+
+	var hosts []string
+	for _, tmpl := range templates {
+		u, err := url.Parse(expand(tmpl, "x"))
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+	return hosts
+}
+
+// ValidHandle returns a *scanner.HandleError if handle cannot exist on the
+// site
+func (p *SitePlugin) ValidHandle(handle string) error {
+	return p.site.validate(p.site.handle(handle))
+}
+
+func (p *SitePlugin) Check(ctx context.Context, target string) ([]models.Finding, error) {
+	target = p.site.handle(target)
+	if err := p.site.validate(target); err != nil {
+		// Not a valid handle on this platform, no request needed
+		return []models.Finding{{
+			PluginName:  p.Name(),
+			Indicator:   p.site.Indicator,
+			Value:       target,
+			Status:      models.StatusInvalidUsername,
+			Severity:    models.SeverityInfo,
+			Description: err.Error(),
+			Timestamp:   time.Now(),
+		}}, nil
+	}
+
+	profileURL := expand(p.site.URL, target)
+	if p.site.ProfileURL != "" {
+		profileURL = expand(p.site.ProfileURL, target)
+	}
+
+	r, resp, err := p.fetch(ctx, p.site.URL, p.site.Request, target, p.site.needsBody())
+	if err != nil {
+		return nil, err
+	}
+	attempts := resp.Attempts
+
+	v, ok := p.site.detect(r)
+	if !ok {
+		switch {
+		case resp.StatusCode == http.StatusForbidden, resp.StatusCode == http.StatusTooManyRequests:
+			return nil, fmt.Errorf("%s rate limit reached or access forbidden: %w", strings.ToLower(p.site.Name), resp.StatusError())
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			// A page was served but carried none of the known markers
+			v = verdict{status: models.StatusUnknown, evidence: []string{fmt.Sprintf("status %d, no signature matched", resp.StatusCode)}}
+		default:
+			return nil, fmt.Errorf("unexpected status code from %s: %w", strings.ToLower(p.site.Name), resp.StatusError())
+		}
+	}
+
+	// A handle that looks absent may still be registered to an account the
+	// platform hides (suspended, flagged); ask the registration endpoint
+	if v.status == models.StatusAvailable && p.site.Confirm != nil {
+		c := p.site.Confirm
+		cr, cresp, err := p.fetch(ctx, c.URL, c.Request, target, c.Taken.needsBody())
+		if err != nil {
+			return nil, err
+		}
+		attempts += cresp.Attempts
+		// A throttled or failing check says nothing about the handle
+		switch {
+		case cresp.StatusCode == http.StatusForbidden, cresp.StatusCode == http.StatusTooManyRequests:
+			return nil, fmt.Errorf("%s registration check rate limited or forbidden: %w", strings.ToLower(p.site.Name), cresp.StatusError())
+		case cresp.StatusCode < 200 || cresp.StatusCode >= 500 || (cresp.StatusCode >= 300 && cresp.StatusCode < 400):
+			return nil, fmt.Errorf("unexpected status code from %s registration check: %w", strings.ToLower(p.site.Name), cresp.StatusError())
+		}
+		for i := range c.Taken {
+			if evidence, ok := c.Taken[i].match(cr); ok {
+				v.status = c.Status
+				for _, e := range evidence {
+					v.evidence = append(v.evidence, "registration check: "+e)
+				}
+				break
+			}
+		}
+	}
+
+	finding := models.Finding{
+		PluginName: p.Name(),
+		Indicator:  p.site.Indicator,
+		Value:      target,
+		Status:     v.status,
+		Metadata: map[string]interface{}{
+			"url":         profileURL,
+			"final_url":   r.finalURL,
+			"http_status": r.status,
+			"evidence":    v.evidence,
+			"attempts":    attempts,
+		},
+		Timestamp: time.Now(),
+	}
+
+	switch v.status {
+	case models.StatusExists, models.StatusPrivate, models.StatusRestricted, models.StatusDeactivated:
+		if p.site.Profile != nil {
+			finding.Profile = p.site.Profile.extract(r)
+		}
+	}
+
+	switch v.status {
+	case models.StatusAvailable:
+		finding.Severity = models.SeverityLow
+		finding.Description = fmt.Sprintf("%s username '%s' is available for registration", p.site.Name, target)
+	case models.StatusSuspended:
+		finding.Severity = models.SeverityMedium
+		finding.Description = fmt.Sprintf("%s account '%s' is suspended by the platform", p.site.Name, target)
+	case models.StatusDeactivated:
+		finding.Severity = models.SeverityMedium
+		finding.Description = fmt.Sprintf("%s account '%s' has been deactivated or memorialized", p.site.Name, target)
+	case models.StatusRestricted:
+		finding.Severity = models.SeverityMedium
+		finding.Description = fmt.Sprintf("%s account '%s' is restricted by the platform: %s", p.site.Name, target, profileURL)
+	case models.StatusPrivate:
+		finding.Severity = models.SeverityInfo
+		finding.Description = fmt.Sprintf("%s profile found but private: %s", p.site.Name, profileURL)
+	case models.StatusExists:
+		finding.Severity = models.SeverityInfo
+		finding.Description = fmt.Sprintf("%s profile found: %s", p.site.Name, profileURL)
+		if finding.Profile != nil && finding.Profile.DisplayName != "" {
+			finding.Description += fmt.Sprintf(" (%s)", finding.Profile.DisplayName)
+		}
+	default:
+		finding.Severity = models.SeverityInfo
+		finding.Description = fmt.Sprintf("Could not determine whether %s username '%s' exists", p.site.Name, target)
+	}
+
+	return []models.Finding{finding}, nil
+}
+
+// fetch sends a probe request and captures what signals need to see
+func (p *SitePlugin) fetch(ctx context.Context, tmpl string, spec Request, target string, withBody bool) (*response, *httpx.Response, error) {
+	var body io.Reader
+	if spec.Body != "" {
+		body = strings.NewReader(expand(spec.Body, target))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, spec.Method, expand(tmpl, target), body)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range spec.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	r := &response{
+		status:   resp.StatusCode,
+		finalURL: resp.Request.URL.String(),
+		username: target,
+	}
+	if withBody {
+		r.body, err = io.ReadAll(io.LimitReader(resp.Body, p.site.MaxBody))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return r, resp, nil
+}
+
+// expand substitutes the username placeholder in a template
+func expand(tmpl, username string) string {
+	return strings.ReplaceAll(tmpl, usernamePlaceholder, url.PathEscape(username))
+}
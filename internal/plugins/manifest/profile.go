@@ -0,0 +1,346 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"golang.org/x/net/html"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileRules tells how to read the details of an account from the probe
+// response. Every field is optional.
+type ProfileRules struct {
+	DisplayName     Rules `yaml:"display_name" json:"display_name"`
+	Bio             Rules `yaml:"bio" json:"bio"`
+	Links           Rules `yaml:"links" json:"links"`                       // every value of every rule is kept
+	VerifiedDomains Rules `yaml:"verified_domains" json:"verified_domains"` // every value of every rule is kept
+	Avatar          Rules `yaml:"avatar" json:"avatar"`
+	Followers       Rules `yaml:"followers" json:"followers"`
+	Following       Rules `yaml:"following" json:"following"`
+	Posts           Rules `yaml:"posts" json:"posts"`
+	Created         Rules `yaml:"created" json:"created"`
+	LastActive      Rules `yaml:"last_active" json:"last_active"` // the latest date of every rule is kept
+	Location        Rules `yaml:"location" json:"location"`
+	Verified        Rules `yaml:"verified" json:"verified"` // any value other than false, 0 or null
+}
+
+// Rule extracts values from one part of the response
+type Rule struct {
+	Meta      string `yaml:"meta" json:"meta"`             // content of <meta> tags by name or property
+	JSON      string `yaml:"json" json:"json"`             // dotted path into a JSON body
+	BodyRegex string `yaml:"body_regex" json:"body_regex"` // first capture group, or the whole match, over the body
+	Regex     string `yaml:"regex" json:"regex"`           // refines each value the same way; values not matching are dropped
+	Format    string `yaml:"format" json:"format"`         // Go time layout of dates
+
+	bodyRe *regexp.Regexp
+	re     *regexp.Regexp
+}
+
+// Rules is a list of alternative rules; the first one yielding a value wins.
+// A single rule may be written without the surrounding list.
+type Rules []Rule
+
+func (r *Rules) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var rule Rule
+		if err := node.Decode(&rule); err != nil {
+			return err
+		}
+		*r = Rules{rule}
+		return nil
+	}
+	var list []Rule
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*r = list
+	return nil
+}
+
+func (r *Rules) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var rule Rule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			return err
+		}
+		*r = Rules{rule}
+		return nil
+	}
+	var list []Rule
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*r = list
+	return nil
+}
+
+// fields pairs every rule list with its name for compilation
+func (p *ProfileRules) fields() map[string]Rules {
+	return map[string]Rules{
+		"display_name": p.DisplayName, "bio": p.Bio, "links": p.Links, "verified_domains": p.VerifiedDomains, "avatar": p.Avatar,
+		"followers": p.Followers, "following": p.Following, "posts": p.Posts,
+		"created": p.Created, "last_active": p.LastActive, "location": p.Location, "verified": p.Verified,
+	}
+}
+
+func (p *ProfileRules) compile() error {
+	for name, rules := range p.fields() {
+		for i := range rules {
+			if err := rules[i].compile(); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (rule *Rule) compile() error {
+	sources := 0
+	for _, s := range []string{rule.Meta, rule.JSON, rule.BodyRegex} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("rule needs exactly one of meta, json, body_regex")
+	}
+	var err error
+	if rule.BodyRegex != "" {
+		if rule.bodyRe, err = regexp.Compile(rule.BodyRegex); err != nil {
+			return fmt.Errorf("invalid body_regex %q: %w", rule.BodyRegex, err)
+		}
+	}
+	if rule.Regex != "" {
+		if rule.re, err = regexp.Compile(rule.Regex); err != nil {
+			return fmt.Errorf("invalid regex %q: %w", rule.Regex, err)
+		}
+	}
+	return nil
+}
+
+// values returns what the rule extracts from the response, in order
+func (rule *Rule) values(r *response) []string {
+	var raw []string
+	switch {
+	case rule.Meta != "":
+		raw = r.document().meta[strings.ToLower(rule.Meta)]
+	case rule.JSON != "":
+		if v, ok := lookupJSON(r.decodeJSON(), rule.JSON); ok && v != nil {
+			if list, ok := v.([]interface{}); ok {
+				for _, item := range list {
+					raw = append(raw, jsonString(item))
+				}
+			} else {
+				raw = append(raw, jsonString(v))
+			}
+		}
+	case rule.bodyRe != nil:
+		for _, m := range rule.bodyRe.FindAllSubmatch(r.body, -1) {
+			raw = append(raw, unescape(string(group(m))))
+		}
+	}
+
+	var out []string
+	for _, v := range raw {
+		if rule.re != nil {
+			m := rule.re.FindStringSubmatch(v)
+			if m == nil {
+				continue
+			}
+			v = m[0]
+			if len(m) > 1 {
+				v = m[1]
+			}
+		}
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// unescape decodes a body_regex capture: the string escapes of embedded
+// JSON, such as \/ and \u00e9, then HTML entities
+func unescape(v string) string {
+	if strings.Contains(v, `\`) {
+		var s string
+		if err := json.Unmarshal([]byte(`"`+v+`"`), &s); err == nil {
+			v = s
+		}
+	}
+	return html.UnescapeString(v)
+}
+
+// group returns the first capture group of a match, or the whole match
+func group(m [][]byte) []byte {
+	if len(m) > 1 {
+		return m[1]
+	}
+	return m[0]
+}
+
+// jsonString formats a decoded JSON value; numbers keep their integer form
+func jsonString(v interface{}) string {
+	if f, ok := v.(float64); ok && f == math.Trunc(f) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return fmt.Sprint(v)
+}
+
+// first returns the first value of the first rule yielding one
+func (rs Rules) first(r *response) (string, bool) {
+	for _, rule := range rs {
+		if v := rule.values(r); len(v) > 0 {
+			return v[0], true
+		}
+	}
+	return "", false
+}
+
+// extract reads the details of an account from the probe response; nil
+// when no rule yields anything
+func (p *ProfileRules) extract(r *response) *models.Profile {
+	prof := &models.Profile{}
+	found := false
+	text := func(rs Rules) string {
+		v, ok := rs.first(r)
+		found = found || ok
+		return v
+	}
+	all := func(rs Rules) []string {
+		var out []string
+		for _, rule := range rs {
+			for _, v := range rule.values(r) {
+				if !slices.Contains(out, v) {
+					out = append(out, v)
+					found = true
+				}
+			}
+		}
+		return out
+	}
+	count := func(rs Rules) *int64 {
+		for _, rule := range rs {
+			for _, v := range rule.values(r) {
+				if n, ok := parseCount(v); ok {
+					found = true
+					return &n
+				}
+			}
+		}
+		return nil
+	}
+
+	prof.DisplayName = text(p.DisplayName)
+	prof.Bio = text(p.Bio)
+	prof.AvatarURL = text(p.Avatar)
+	prof.Location = text(p.Location)
+	prof.Followers = count(p.Followers)
+	prof.Following = count(p.Following)
+	prof.Posts = count(p.Posts)
+	prof.Links = all(p.Links)
+	prof.VerifiedDomains = all(p.VerifiedDomains)
+	for _, rule := range p.Created {
+		if t, ok := firstDate(rule.values(r), rule.Format); ok {
+			prof.CreatedAt = &t
+			found = true
+			break
+		}
+	}
+	for _, rule := range p.LastActive {
+		for _, v := range rule.values(r) {
+			if t, ok := firstDate([]string{v}, rule.Format); ok && (prof.LastActive == nil || t.After(*prof.LastActive)) {
+				prof.LastActive = &t
+				found = true
+			}
+		}
+	}
+	if v, ok := p.Verified.first(r); ok {
+		switch strings.ToLower(v) {
+		case "false", "0", "null":
+		default:
+			prof.Verified = true
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return prof
+}
+
+// countRe matches follower-style counts: 1234, 1,234, 12.5K, 3M
+var countRe = regexp.MustCompile(`(?i)^([0-9][0-9.,\s]*)\s*([kmb])?\b`)
+
+// parseCount reads a count as platforms display it, with thousands
+// separators or a K, M or B suffix
+func parseCount(s string) (int64, bool) {
+	m := countRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, false
+	}
+	num := strings.ReplaceAll(strings.TrimSpace(m[1]), " ", "")
+	mult := 1.0
+	switch strings.ToLower(m[2]) {
+	case "k":
+		mult = 1e3
+	case "m":
+		mult = 1e6
+	case "b":
+		mult = 1e9
+	}
+	if mult == 1 {
+		// Without a suffix, separators only group thousands
+		num = strings.NewReplacer(",", "", ".", "").Replace(num)
+	} else {
+		num = strings.ReplaceAll(num, ",", ".")
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int64(math.Round(f * mult)), true
+}
+
+// dateLayouts are the date formats tried when a rule sets no format
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RubyDate, // Twitter's created_at
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"January 2, 2006",
+	"January 2006",
+}
+
+// firstDate parses the first value that is a date: in the given layout, one
+// of the common layouts, or Unix seconds
+func firstDate(values []string, layout string) (time.Time, bool) {
+	layouts := dateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, v := range values {
+		for _, l := range layouts {
+			if t, err := time.Parse(l, v); err == nil {
+				return t.UTC(), true
+			}
+		}
+		if layout == "" {
+			if sec, err := strconv.ParseInt(v, 10, 64); err == nil && sec > 0 {
+				return time.Unix(sec, 0).UTC(), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -3,6 +3,7 @@ package plugins
 import (
 	"context"
 
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
 	"github.com/ismailtsdln/socialrecon/internal/models"
 )
 
@@ -13,3 +14,20 @@ type Plugin interface {
 	// Check assesses the presence/risk of a specific indicator (username/brand)
 	Check(ctx context.Context, target string) ([]models.Finding, error)
 }
+
+// HTTPPlugin is implemented by plugins that route their outbound HTTP
+// traffic through the engine's shared, rate-limited client
+type HTTPPlugin interface {
+	Plugin
+	SetClient(c *httpx.Client)
+	// Hosts lists the destination hosts the plugin talks to
+	Hosts() []string
+}
+
+// HandleValidator is implemented by plugins that know which handles can
+// exist on their platform, so generated handles can be screened without a
+// request
+type HandleValidator interface {
+	Plugin
+	ValidHandle(handle string) error
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limiter throttles requests with one token bucket per destination host.
+// A rate of zero or less means unlimited.
+type Limiter struct {
+	mu        sync.Mutex
+	rate      float64 // default requests per second
+	burst     int
+	overrides map[string]float64
+	buckets   map[string]*bucket
+}
+
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a limiter with a default per-host rate. If burst is not
+// positive it defaults to the rate rounded up (at least 1).
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{
+		rate:      rate,
+		burst:     burst,
+		overrides: make(map[string]float64),
+		buckets:   make(map[string]*bucket),
+	}
+}
+
+// SetRate overrides the rate for a single host
+func (l *Limiter) SetRate(host string, rate float64) {
+	host = normalize(host)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides[host] = rate
+	if b, ok := l.buckets[host]; ok {
+		b.rate = rate
+		b.burst = l.burstFor(rate)
+		b.tokens = math.Min(b.tokens, b.burst)
+	}
+}
+
+// Rate returns the effective rate for a host
+func (l *Limiter) Rate(host string) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rateFor(normalize(host))
+}
+
+// Wait blocks until a request to host is allowed or ctx is done
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	host = normalize(host)
+	delay := l.reserve(host)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.refund(host)
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the host bucket and returns how long the
+// caller has to wait for it
+func (l *Limiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[host]
+	if !ok {
+		rate := l.rateFor(host)
+		b = &bucket{rate: rate, burst: l.burstFor(rate), last: now}
+		b.tokens = b.burst
+		l.buckets[host] = b
+	}
+	if b.rate <= 0 {
+		return 0
+	}
+
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refund returns a token reserved by a cancelled waiter
+func (l *Limiter) refund(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[host]; ok && b.rate > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+1)
+	}
+}
+
+func (l *Limiter) rateFor(host string) float64 {
+	if r, ok := l.overrides[host]; ok {
+		return r
+	}
+	return l.rate
+}
+
+func (l *Limiter) burstFor(rate float64) float64 {
+	if l.burst > 0 {
+		return float64(l.burst)
+	}
+	return math.Max(1, math.Ceil(rate))
+}
+
+// normalize maps hosts to their bucket key ("www.github.com:443" -> "github.com")
+func normalize(host string) string {
+	host = strings.ToLower(host)
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	return strings.TrimPrefix(host, "www.")
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Wait(t *testing.T) {
+	l := NewLimiter(20, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx, "github.com"); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	// First token is free, the next two take 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests at 20/s took %v, want >= 100ms", elapsed)
+	}
+
+	// Other hosts have their own bucket
+	start = time.Now()
+	if err := l.Wait(ctx, "twitter.com"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("first request to a new host waited %v", elapsed)
+	}
+}
+
+func TestLimiter_SetRate(t *testing.T) {
+	l := NewLimiter(1, 0)
+	l.SetRate("WWW.GitHub.com:443", 0.5)
+
+	if got := l.Rate("github.com"); got != 0.5 {
+		t.Errorf("Rate(github.com) = %v, want 0.5", got)
+	}
+	if got := l.Rate("instagram.com"); got != 1 {
+		t.Errorf("Rate(instagram.com) = %v, want 1", got)
+	}
+}
+
+func TestLimiter_WaitCancelled(t *testing.T) {
+	l := NewLimiter(0.1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx, "github.com"); err != nil {
+		t.Fatalf("first Wait() error = %v", err)
+	}
+	if err := l.Wait(ctx, "github.com"); err == nil {
+		t.Errorf("Wait() should fail once the context is done")
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := NewLimiter(0, 0)
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		l.Wait(context.Background(), "github.com")
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("unlimited limiter waited %v", elapsed)
+	}
+}
@@ -17,7 +17,16 @@ func NewReporter() *Reporter {
 
 // ExportJSON writes the result to a JSON file or stdout
 func (r *Reporter) ExportJSON(result *models.ScanResult, filename string) error {
-	data, err := json.MarshalIndent(result, "", "  ")
+	return r.writeJSON(result, filename)
+}
+
+// ExportBatchJSON writes a multi-target result to a JSON file or stdout
+func (r *Reporter) ExportBatchJSON(batch *models.BatchResult, filename string) error {
+	return r.writeJSON(batch, filename)
+}
+
+func (r *Reporter) writeJSON(v interface{}, filename string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -34,8 +43,33 @@ func (r *Reporter) ExportJSON(result *models.ScanResult, filename string) error
 func (r *Reporter) PrintSummary(result *models.ScanResult) {
 	fmt.Printf("\n--- Scan Summary ---\n")
 	fmt.Printf("Target:     %s\n", result.Target)
-	fmt.Printf("Findings:   %d\n", len(result.Findings))
+	fmt.Printf("Findings:   %d\n", result.FindingCount())
+	fmt.Printf("Checks:     %d (%d inconclusive)\n", result.ExecutionCount(), len(result.Inconclusive()))
 	fmt.Printf("Risk Score: %.2f/100\n", result.RiskScore)
+	if g := result.Graph; g != nil {
+		fmt.Printf("Graph:      %d nodes, %d relations\n", len(g.Nodes), len(g.Edges))
+	}
 	fmt.Printf("Duration:   %v\n", result.EndTime.Sub(result.StartTime))
 	fmt.Printf("-------------------\n")
 }
+
+// PrintBatchSummary outputs a text-based summary of a multi-target scan
+func (r *Reporter) PrintBatchSummary(batch *models.BatchResult) {
+	findings, checks, inconclusive := 0, 0, 0
+	for _, res := range batch.Results {
+		findings += res.FindingCount()
+		checks += res.ExecutionCount()
+		inconclusive += len(res.Inconclusive())
+	}
+
+	fmt.Printf("\n--- Batch Summary ---\n")
+	fmt.Printf("Targets:    %d\n", len(batch.Results))
+	fmt.Printf("Findings:   %d\n", findings)
+	fmt.Printf("Checks:     %d (%d inconclusive)\n", checks, inconclusive)
+	fmt.Printf("Max Risk:   %.2f/100\n", batch.RiskScore)
+	fmt.Printf("Duration:   %v\n", batch.EndTime.Sub(batch.StartTime))
+	fmt.Printf("--------------------\n")
+	for _, res := range batch.Results {
+		fmt.Printf("  %-30s %6.2f  (%d findings)\n", res.Target, res.RiskScore, res.FindingCount())
+	}
+}
@@ -1,8 +1,10 @@
 package report
 
 import (
+	"fmt"
 	"html/template"
 	"os"
+	"strings"
 
 	"github.com/ismailtsdln/socialrecon/internal/models"
 )
@@ -13,7 +15,7 @@ const htmlTemplate = `
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>SocialRecon Report - {{.Target}}</title>
+    <title>SocialRecon Report{{if eq (len .Results) 1}} - {{(index .Results 0).Target}}{{end}}</title>
     <style>
         body { font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; line-height: 1.6; color: #333; max-width: 1000px; margin: 0 auto; padding: 20px; background: #f4f7f6; }
         .header { background: #1a202c; color: white; padding: 30px; border-radius: 8px; margin-bottom: 30px; }
@@ -33,12 +35,30 @@ const htmlTemplate = `
         .badge { padding: 4px 8px; border-radius: 4px; font-size: 0.8em; font-weight: bold; }
         .badge-exists { background: #ebf8ff; color: #2b6cb0; }
         .badge-available { background: #f0fff4; color: #2f855a; }
+        .badge-private { background: #ebf8ff; color: #2c5282; }
+        .badge-suspended, .badge-deactivated, .badge-restricted, .badge-abandoned { background: #fffff0; color: #b7791f; }
+        .badge-unknown, .badge-invalid_username { background: #edf2f7; color: #4a5568; }
+        .badge-rate_limited, .badge-error { background: #fff5f7; color: #b83280; }
+        .badge-inconclusive, .badge-failed { background: #fffaf0; color: #c05621; }
+        .badge-verified { background: #f0fff4; color: #2f855a; }
+        .badge-claimed_one_way { background: #fffff0; color: #b7791f; }
+        .badge-unrelated { background: #edf2f7; color: #4a5568; }
+        .badge-links_to { background: #edf2f7; color: #2d3748; }
+        .badge-same_avatar, .badge-same_name { background: #faf5ff; color: #6b46c1; }
+        .muted { color: #718096; }
+        .target { margin-bottom: 40px; }
+        .identity { margin: 20px 0 30px; }
+        .sources { margin: 0 0 10px; padding-left: 20px; font-size: 0.9em; }
+        .profile { display: flex; gap: 10px; margin-top: 8px; font-size: 0.9em; }
+        .profile .avatar { width: 40px; height: 40px; border-radius: 50%; }
+        .verified { color: #3182ce; }
     </style>
 </head>
 <body>
     <div class="header">
         <h1>SocialRecon Executive Report</h1>
-        <p>Target: <strong>{{.Target}}</strong></p>
+        {{if eq (len .Results) 1}}<p>Target: <strong>{{(index .Results 0).Target}}</strong></p>
+        {{else}}<p>Targets: <strong>{{len .Results}}</strong></p>{{end}}
         <p>Scan completed on {{.EndTime.Format "2006-01-02 15:04:05"}}</p>
     </div>
 
@@ -49,7 +69,11 @@ const htmlTemplate = `
         </div>
         <div class="card">
             <h3>Total Findings</h3>
-            <div class="value">{{len .Findings}}</div>
+            <div class="value">{{totalFindings .}}</div>
+        </div>
+        <div class="card">
+            <h3>Inconclusive Checks</h3>
+            <div class="value">{{totalInconclusive .}}</div>
         </div>
         <div class="card">
             <h3>Duration</h3>
@@ -57,7 +81,111 @@ const htmlTemplate = `
         </div>
     </div>
 
-    <h2>Findings Detail</h2>
+    {{$batch := gt (len .Results) 1}}
+    {{range .Results}}
+    <div class="target">
+    {{if $batch}}
+    <h2>{{.Target}} <span class="muted">({{.TargetType}}) &middot; risk {{printf "%.1f" .RiskScore}}/100</span></h2>
+    {{end}}
+
+    {{with .Findings}}
+    <h3>Target Findings</h3>
+    {{template "findings" .}}
+    {{end}}
+
+    {{range .Identities}}
+    <div class="identity">
+    <h3>@{{.Username}}{{with .Ownership}} <span class="badge badge-{{.}}">{{.}}</span>{{end}}</h3>
+    {{if .Sources}}
+    <ul class="sources muted">
+        {{range .Sources}}<li>{{with .Platform}}{{.}} link {{end}}<a href="{{.URL}}">{{.URL}}</a>{{range .Redirects}} &rarr; {{.}}{{end}}{{with .Via}} via {{.}}{{end}}{{with .Page}} found on <a href="{{.}}">{{.}}</a>{{end}}</li>{{end}}
+    </ul>
+    {{else if .Permutation}}
+    <p class="muted">Lookalike of @{{.Permutation.Of}}: {{.Permutation.Detail}}</p>
+    {{else}}
+    <p class="muted">Scanned as given</p>
+    {{end}}
+    {{template "findings" .Findings}}
+    </div>
+    {{else}}
+    <p class="muted">No social identities discovered</p>
+    {{end}}
+
+    {{with .Graph}}{{if .Edges}}
+    <h3>Identity Graph</h3>
+    <p class="muted">{{graphCounts .}}</p>
+    <table>
+        <thead>
+            <tr>
+                <th>From</th>
+                <th>Relation</th>
+                <th>To</th>
+                <th>Detail</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{$graph := .}}
+            {{range .Edges}}
+            <tr>
+                <td>{{nodeLabel $graph .From}}</td>
+                <td><span class="badge badge-{{.Kind}}">{{.Kind}}</span></td>
+                <td>{{nodeLabel $graph .To}}</td>
+                <td class="muted">{{.Detail}}</td>
+            </tr>
+            {{end}}
+        </tbody>
+    </table>
+    {{end}}{{end}}
+
+    {{with .Inconclusive}}
+    <h3>Inconclusive Checks</h3>
+    <p class="muted">These checks could not determine whether the account exists; their platforms are not covered by the findings above.</p>
+    <table>
+        <thead>
+            <tr>
+                <th>Platform</th>
+                <th>Target</th>
+                <th>Outcome</th>
+                <th>Reason</th>
+                <th>Details</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{range .}}
+            <tr>
+                <td><strong>{{.Plugin}}</strong></td>
+                <td>{{.Target}}</td>
+                <td><span class="badge badge-{{.Outcome}}">{{.Outcome}}</span></td>
+                <td>{{.ErrorClass}}</td>
+                <td>{{.Message}}</td>
+            </tr>
+            {{end}}
+        </tbody>
+    </table>
+    {{end}}
+    </div>
+    {{end}}
+</body>
+</html>
+{{define "profile"}}
+    <div class="profile">
+        {{with .AvatarURL}}<img src="{{.}}" alt="" class="avatar">{{end}}
+        <div>
+            {{with .DisplayName}}<strong>{{.}}</strong>{{end}}{{if .Verified}} <span class="verified" title="Verified">&#10003;</span>{{end}}
+            {{with .Bio}}<div>{{.}}</div>{{end}}
+            <div class="muted">
+                {{with .Followers}}{{deref .}} followers{{end}}
+                {{with .Following}} &middot; {{deref .}} following{{end}}
+                {{with .Posts}} &middot; {{deref .}} posts{{end}}
+                {{with .Location}} &middot; {{.}}{{end}}
+                {{with .CreatedAt}} &middot; joined {{.Format "Jan 2006"}}{{end}}
+                {{with .LastActive}} &middot; last active {{.Format "Jan 2006"}}{{end}}
+            </div>
+            {{range .Links}}<div><a href="{{.}}">{{.}}</a></div>{{end}}
+        </div>
+    </div>
+{{end}}
+{{define "findings"}}
     <table>
         <thead>
             <tr>
@@ -69,24 +197,83 @@ const htmlTemplate = `
             </tr>
         </thead>
         <tbody>
-            {{range .Findings}}
+            {{range .}}
             <tr>
                 <td><strong>{{.PluginName}}</strong></td>
                 <td>{{.Indicator}}</td>
                 <td><span class="badge badge-{{.Status}}">{{.Status}}</span></td>
                 <td><span class="severity-{{.Severity}}">{{.Severity}}</span></td>
-                <td>{{.Description}}</td>
+                <td>{{.Description}}{{with .Profile}}{{template "profile" .}}{{end}}</td>
             </tr>
+            {{else}}
+            <tr><td colspan="5" class="muted">No findings</td></tr>
             {{end}}
         </tbody>
     </table>
-</body>
-</html>
+{{end}}
 `
 
+var templateFuncs = template.FuncMap{
+	"deref": func(n *int64) int64 { return *n },
+	"totalFindings": func(b *models.BatchResult) int {
+		n := 0
+		for _, r := range b.Results {
+			n += r.FindingCount()
+		}
+		return n
+	},
+	"nodeLabel": func(g *models.Graph, id string) string {
+		n, ok := g.Node(id)
+		if !ok {
+			return id
+		}
+		if n.Platform != "" {
+			return n.Label + " (" + n.Platform + ")"
+		}
+		return n.Label
+	},
+	"graphCounts": func(g *models.Graph) string {
+		counts := make(map[models.NodeKind]int)
+		for _, n := range g.Nodes {
+			counts[n.Kind]++
+		}
+		var parts []string
+		for _, k := range []models.NodeKind{models.NodeDomain, models.NodeAccount, models.NodeEmail, models.NodeURL} {
+			if counts[k] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[k], plural(string(k), counts[k])))
+			}
+		}
+		return fmt.Sprintf("%s, %d %s", strings.Join(parts, ", "), len(g.Edges), plural("relation", len(g.Edges)))
+	},
+	"totalInconclusive": func(b *models.BatchResult) int {
+		n := 0
+		for _, r := range b.Results {
+			n += len(r.Inconclusive())
+		}
+		return n
+	},
+}
+
+func plural(word string, n int) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
 // ExportHTML generates a nice dashboard report
 func (r *Reporter) ExportHTML(result *models.ScanResult, filename string) error {
-	tmpl, err := template.New("report").Parse(htmlTemplate)
+	return r.ExportBatchHTML(&models.BatchResult{
+		Results:   []*models.ScanResult{result},
+		StartTime: result.StartTime,
+		EndTime:   result.EndTime,
+		RiskScore: result.RiskScore,
+	}, filename)
+}
+
+// ExportBatchHTML generates a dashboard report with a section per target
+func (r *Reporter) ExportBatchHTML(batch *models.BatchResult, filename string) error {
+	tmpl, err := template.New("report").Funcs(templateFuncs).Parse(htmlTemplate)
 	if err != nil {
 		return err
 	}
@@ -97,5 +284,5 @@ func (r *Reporter) ExportHTML(result *models.ScanResult, filename string) error
 	}
 	defer f.Close()
 
-	return tmpl.Execute(f, result)
+	return tmpl.Execute(f, batch)
 }
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestReporter_ExportHTML(t *testing.T) {
+	followers := int64(1200)
+	joined := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	result := &models.ScanResult{
+		Target:     "acme",
+		TargetType: models.TargetUsername,
+		Identities: []models.Identity{
+			{
+				Username: "acme",
+				Findings: []models.Finding{{
+					PluginName: "GitHub",
+					Indicator:  "github_profile",
+					Status:     models.StatusExists,
+					Profile: &models.Profile{
+						DisplayName: "Acme Inc",
+						Followers:   &followers,
+						CreatedAt:   &joined,
+						Verified:    true,
+						Links:       []string{"https://acme.example"},
+					},
+				}},
+			},
+			{
+				Username:  "acmehq",
+				Sources:   []models.Source{{Platform: "Twitter", URL: "https://x.com/acmehq", Page: "https://acme.example/"}},
+				Ownership: models.OwnershipOneWay,
+			},
+			{
+				Username:    "acme_hq",
+				Permutation: &models.Permutation{Of: "acme", Transform: "affix", Detail: "suffix 'hq'"},
+			},
+		},
+		Graph: &models.Graph{
+			Nodes: []models.Node{
+				{ID: "domain:acme.example", Kind: models.NodeDomain, Label: "acme.example"},
+				{ID: "account:twitter:acmehq", Kind: models.NodeAccount, Label: "@acmehq", Platform: "Twitter"},
+			},
+			Edges: []models.Edge{{From: "domain:acme.example", To: "account:twitter:acmehq", Kind: models.EdgeLinksTo, Detail: "https://acme.example/"}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := NewReporter().ExportHTML(result, path); err != nil {
+		t.Fatalf("ExportHTML() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"Acme Inc", "1200 followers", "joined Mar 2015", "https://acme.example", // profile
+		"https://x.com/acmehq",                    // source
+		`class="badge badge-claimed_one_way"`,     // ownership
+		"Lookalike of @acme: suffix &#39;hq&#39;", // permutation
+		"Scanned as given",
+		"1 domain, 1 account, 1 relation", "@acmehq (Twitter)", // graph
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package scanner
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
+)
+
+const (
+	userAgent = "Mozilla/5.0 (compatible; SocialRecon/1.0)"
+	// robotsAgent is the token matched against robots.txt User-agent lines
+	robotsAgent = "socialrecon"
+
+	maxPageSize    = 2 << 20
+	maxSitemapSize = 5 << 20
+	maxSitemaps    = 5
+	maxSitemapURLs = 1000
+	maxScripts     = 10 // same-site script files fetched per site
+	maxExpansions  = 50 // distinct shortened links expanded per site
+)
+
+// CrawlOptions bounds a domain crawl
+type CrawlOptions struct {
+	MaxDepth     int  // link hops followed from the start page (0 = start page only)
+	MaxPages     int  // pages fetched per site, including the start page
+	IgnoreRobots bool // fetch pages disallowed by robots.txt
+	NoSitemap    bool // do not seed the crawl from sitemap.xml
+	ExpandLinks  bool // follow shortened links (bit.ly, t.co, ...) over the network
+}
+
+// DefaultCrawlOptions returns the bounds used by the CLI
+func DefaultCrawlOptions() CrawlOptions {
+	return CrawlOptions{MaxDepth: 2, MaxPages: 25}
+}
+
+// priorityPaths are the pages most likely to link social profiles; they are
+// visited first within each depth
+var priorityPaths = []string{"about", "contact", "press", "media", "team", "company", "social", "connect", "community"}
+
+// skippedExts are links that never lead to HTML pages
+var skippedExts = map[string]bool{
+	".pdf": true, ".zip": true, ".gz": true, ".tar": true, ".dmg": true, ".exe": true,
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".svg": true, ".webp": true, ".ico": true,
+	".mp3": true, ".mp4": true, ".mov": true, ".webm": true, ".avi": true,
+	".css": true, ".js": true, ".json": true, ".xml": true, ".txt": true, ".rss": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".eot": true,
+}
+
+// crawlItem is a page waiting to be fetched
+type crawlItem struct {
+	url   *url.URL
+	depth int
+}
+
+// crawler holds the state of a single site crawl
+type crawler struct {
+	e       *Extractor
+	origin  *url.URL
+	robots  *robots
+	visited map[string]bool
+	queue   []crawlItem
+	fetched int
+	infos   []Info
+	seen    map[string]bool
+
+	scripts    []*url.URL
+	scriptSeen map[string]bool
+	short      []pending
+}
+
+// crawl fetches the start page, then breadth-first every same-site page
+// reachable within the configured depth and page limits
+func (e *Extractor) crawl(ctx context.Context, targetURL string) ([]Info, error) {
+	start, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", targetURL, err)
+	}
+
+	first, err := e.fetchPage(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// The start page may redirect to another host (e.g. www.); that host
+	// defines the site from here on
+	c := &crawler{
+		e:       e,
+		origin:  first.url,
+		robots:  &robots{},
+		visited: make(map[string]bool),
+		seen:    make(map[string]bool),
+		fetched: 1,
+
+		scriptSeen: make(map[string]bool),
+	}
+	c.visit(first)
+	c.add(first)
+
+	if !e.opts.IgnoreRobots || !e.opts.NoSitemap {
+		c.robots = e.fetchRobots(ctx, c.origin)
+	}
+
+	if e.opts.MaxDepth > 0 && e.opts.MaxPages > 1 {
+		c.enqueue(first.links, 1)
+		if !e.opts.NoSitemap {
+			c.enqueue(e.sitemapURLs(ctx, c.origin, c.robots.sitemaps), 1)
+		}
+
+		for len(c.queue) > 0 && c.fetched < e.opts.MaxPages && ctx.Err() == nil {
+			item := c.queue[0]
+			c.queue = c.queue[1:]
+
+			p, err := e.fetchPage(ctx, item.url)
+			c.fetched++
+			if err != nil {
+				continue
+			}
+			if !sameSite(p.url, c.origin) {
+				// Redirected off-site; the links there are not the site's own
+				continue
+			}
+			c.visit(p)
+			c.add(p)
+			if item.depth < e.opts.MaxDepth {
+				c.enqueue(p.links, item.depth+1)
+			}
+		}
+	}
+
+	// Scripts shared by many pages are fetched once, after the pages
+	for i := 0; i < len(c.scripts) && i < maxScripts && ctx.Err() == nil; i++ {
+		p, err := e.fetchScript(ctx, c.scripts[i])
+		if err != nil {
+			continue
+		}
+		c.add(p)
+	}
+
+	if e.opts.ExpandLinks {
+		c.expandShort(ctx)
+	}
+
+	return c.infos, nil
+}
+
+// visit marks a page and every URL that redirected to it as visited
+func (c *crawler) visit(p *page) {
+	c.visited[pageKey(p.url)] = true
+	for _, raw := range p.chain {
+		if u, err := url.Parse(raw); err == nil {
+			c.visited[pageKey(u)] = true
+		}
+	}
+}
+
+// expandShort follows the shortened links found on the site, each once, and
+// records those that lead to a profile
+func (c *crawler) expandShort(ctx context.Context) {
+	type expansion struct {
+		info  Info
+		chain []string
+		ok    bool
+	}
+	cache := make(map[string]*expansion)
+
+	for _, s := range c.short {
+		if ctx.Err() != nil {
+			return
+		}
+		key := s.link.String()
+		x, done := cache[key]
+		if !done {
+			if len(cache) >= maxExpansions {
+				continue
+			}
+			x = &expansion{}
+			cache[key] = x
+			chain, err := c.e.expand(ctx, s.link)
+			if err == nil && len(chain) > 0 {
+				x.info, x.ok = matchLink(chain[len(chain)-1])
+				x.chain = chain
+			}
+		}
+		if !x.ok {
+			continue
+		}
+		info := x.info
+		info.URL = key
+		info.Page = s.page
+		info.Via = s.via
+		info.Redirects = x.chain
+		c.record(info)
+	}
+}
+
+// add records the social links of a page and queues its same-site scripts
+// and shortened links
+func (c *crawler) add(p *page) {
+	for _, info := range p.infos {
+		c.record(info)
+	}
+	c.short = append(c.short, p.short...)
+	for _, u := range p.scripts {
+		key := pageKey(u)
+		if c.scriptSeen[key] || !c.fetchable(u) {
+			continue
+		}
+		c.scriptSeen[key] = true
+		c.scripts = append(c.scripts, u)
+	}
+}
+
+// record keeps a link once per page and extraction source
+func (c *crawler) record(info Info) {
+	key := strings.Join([]string{info.Platform, info.Username, info.URL, info.Page, info.Via}, "\n")
+	if !c.seen[key] {
+		c.seen[key] = true
+		c.infos = append(c.infos, info)
+	}
+}
+
+// enqueue queues the crawlable links not seen before, likely profile-linking
+// pages first
+func (c *crawler) enqueue(links []*url.URL, depth int) {
+	var items []crawlItem
+	for _, u := range links {
+		if !c.crawlable(u) {
+			continue
+		}
+		key := pageKey(u)
+		if c.visited[key] {
+			continue
+		}
+		c.visited[key] = true
+		items = append(items, crawlItem{url: u, depth: depth})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return isPriority(items[i].url) && !isPriority(items[j].url)
+	})
+	c.queue = append(c.queue, items...)
+}
+
+// crawlable reports whether a link points to an HTML page of the site that
+// robots.txt lets us fetch
+func (c *crawler) crawlable(u *url.URL) bool {
+	return c.fetchable(u) && !skippedExts[strings.ToLower(path.Ext(u.Path))]
+}
+
+// fetchable reports whether a URL is on the site and allowed by robots.txt
+func (c *crawler) fetchable(u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	if !sameSite(u, c.origin) {
+		return false
+	}
+	return c.e.opts.IgnoreRobots || c.robots.allowed(u.RequestURI())
+}
+
+// fetchPage fetches and parses a single page, following HTTP redirects and
+// meta refreshes. Non-HTML responses yield an empty page.
+func (e *Extractor) fetchPage(ctx context.Context, u *url.URL) (*page, error) {
+	p, err := e.fetchDocument(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	for hop := 0; p.refresh != nil && hop < maxRedirects; hop++ {
+		if pageKey(p.refresh) == pageKey(p.url) {
+			break
+		}
+		next, err := e.fetchDocument(ctx, p.refresh)
+		if err != nil {
+			// The refreshing page itself was fetched fine
+			break
+		}
+		// Keep what the intermediate page linked to
+		next.chain = append(p.chain, next.chain...)
+		next.infos = append(p.infos, next.infos...)
+		next.links = append(p.links, next.links...)
+		next.scripts = append(p.scripts, next.scripts...)
+		next.short = append(p.short, next.short...)
+		p = next
+	}
+	return p, nil
+}
+
+// fetchDocument fetches and parses a single document
+func (e *Extractor) fetchDocument(ctx context.Context, u *url.URL) (*page, error) {
+	resp, err := e.get(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch URL: %s: %w", u, &httpx.StatusError{Host: u.Hostname(), StatusCode: resp.StatusCode, Attempts: 1})
+	}
+
+	final := resp.Request.URL
+	chain := redirectChain(resp)
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		if mt, _, err := mime.ParseMediaType(ct); err == nil && mt != "text/html" && mt != "application/xhtml+xml" {
+			return &page{url: final, chain: chain}, nil
+		}
+	}
+
+	p := e.parseHTML(io.LimitReader(resp.Body, maxPageSize), final)
+	p.chain = chain
+	return p, nil
+}
+
+// fetchScript fetches a script file and extracts the profiles it links
+func (e *Extractor) fetchScript(ctx context.Context, u *url.URL) (*page, error) {
+	resp, err := e.get(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch script: %s (status %d)", u, resp.StatusCode)
+	}
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt == "text/html" {
+		// Error pages served with 200
+		return nil, fmt.Errorf("not a script: %s", u)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, err
+	}
+	p := &page{url: u}
+	for _, link := range scriptLinks(string(body)) {
+		if l, err := u.Parse(link); err == nil {
+			p.link(l, ViaLinkedScript)
+		}
+	}
+	return p, nil
+}
+
+// fetchRobots loads the robots.txt of the site. A missing or unreadable file
+// allows everything.
+func (e *Extractor) fetchRobots(ctx context.Context, origin *url.URL) *robots {
+	u := &url.URL{Scheme: origin.Scheme, Host: origin.Host, Path: "/robots.txt"}
+	resp, err := e.get(ctx, u.String())
+	if err != nil {
+		return &robots{}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &robots{}
+	}
+	return parseRobots(io.LimitReader(resp.Body, maxPageSize), robotsAgent)
+}
+
+// sitemapURLs collects the same-site page URLs listed in the site's sitemaps,
+// following sitemap indexes for up to maxSitemaps documents
+func (e *Extractor) sitemapURLs(ctx context.Context, origin *url.URL, declared []string) []*url.URL {
+	queue := declared
+	if len(queue) == 0 {
+		queue = []string{(&url.URL{Scheme: origin.Scheme, Host: origin.Host, Path: "/sitemap.xml"}).String()}
+	}
+
+	var urls []*url.URL
+	fetched := 0
+	for len(queue) > 0 && fetched < maxSitemaps && len(urls) < maxSitemapURLs {
+		loc := queue[0]
+		queue = queue[1:]
+		fetched++
+
+		sm, err := e.fetchSitemap(ctx, loc)
+		if err != nil {
+			continue
+		}
+		for _, s := range sm.Sitemaps {
+			queue = append(queue, strings.TrimSpace(s.Loc))
+		}
+		for _, entry := range sm.URLs {
+			u, err := url.Parse(strings.TrimSpace(entry.Loc))
+			if err != nil || !sameSite(u, origin) {
+				continue
+			}
+			urls = append(urls, u)
+			if len(urls) >= maxSitemapURLs {
+				break
+			}
+		}
+	}
+	return urls
+}
+
+// sitemap covers both <urlset> and <sitemapindex> documents
+type sitemap struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+func (e *Extractor) fetchSitemap(ctx context.Context, loc string) (*sitemap, error) {
+	resp, err := e.get(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch sitemap: %s (status %d)", loc, resp.StatusCode)
+	}
+
+	var sm sitemap
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxSitemapSize)).Decode(&sm); err != nil {
+		return nil, err
+	}
+	return &sm, nil
+}
+
+func (e *Extractor) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	return e.client.Do(req)
+}
+
+// siteHost returns the host of u without port and leading "www."
+func siteHost(u *url.URL) string {
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// sameSite reports whether two URLs belong to the same site, treating http
+// and https and the www. prefix as equivalent
+func sameSite(a, b *url.URL) bool {
+	return siteHost(a) == siteHost(b)
+}
+
+// pageKey identifies a page for deduplication: fragments, the scheme, the
+// www. prefix and trailing slashes do not make a different page
+func pageKey(u *url.URL) string {
+	p := strings.TrimSuffix(u.EscapedPath(), "/")
+	if p == "" {
+		p = "/"
+	}
+	key := siteHost(u) + p
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+func isPriority(u *url.URL) bool {
+	p := strings.ToLower(u.Path)
+	for _, k := range priorityPaths {
+		if strings.Contains(p, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/httpx"
+)
+
+func newSite(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, ".txt"):
+			w.Header().Set("Content-Type", "text/plain")
+		case strings.HasSuffix(r.URL.Path, ".js"):
+			w.Header().Set("Content-Type", "application/javascript")
+		case strings.HasSuffix(r.URL.Path, ".xml"):
+			w.Header().Set("Content-Type", "application/xml")
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+		fmt.Fprint(w, strings.ReplaceAll(body, "{origin}", srv.URL))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func usernames(infos []Info) []string {
+	var list []string
+	for _, i := range infos {
+		list = append(list, i.Platform+":"+i.Username)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestExtractor_Crawl(t *testing.T) {
+	srv := newSite(t, map[string]string{
+		"/": `<a href="/about">About</a> <a href="https://twitter.com/acme">Twitter</a>
+			<a href="https://elsewhere.example/contact">Off-site</a> <a href="/private/team">Team</a>
+			<a href="/brochure.pdf">PDF</a>`,
+		"/about":        `<a href="/about/press#top">Press</a> <a href="https://github.com/acme-inc">GitHub</a>`,
+		"/about/press":  `<a href="https://instagram.com/acme.press">Instagram</a> <a href="/deep">Deep</a>`,
+		"/deep":         `<a href="https://tiktok.com/@acmedeep">TikTok</a>`,
+		"/private/team": `<a href="https://linkedin.com/in/acme-private">LinkedIn</a>`,
+		"/careers":      `<a href="https://twitter.com/acmejobs">Jobs</a>`,
+		"/robots.txt":   "User-agent: *\nDisallow: /private\n\nSitemap: {origin}/sitemap.xml\n",
+		"/sitemap.xml":  `<urlset><url><loc>{origin}/careers</loc></url><url><loc>https://elsewhere.example/x</loc></url></urlset>`,
+	})
+
+	tests := []struct {
+		name string
+		opts CrawlOptions
+		want []string
+	}{
+		{
+			name: "Landing page only",
+			opts: CrawlOptions{MaxDepth: 0, MaxPages: 10},
+			want: []string{"Twitter:acme"},
+		},
+		{
+			name: "Depth and sitemap",
+			opts: CrawlOptions{MaxDepth: 2, MaxPages: 10},
+			want: []string{"GitHub:acme-inc", "Instagram:acme.press", "Twitter:acme", "Twitter:acmejobs"},
+		},
+		{
+			name: "Ignoring robots and sitemap",
+			opts: CrawlOptions{MaxDepth: 3, MaxPages: 10, IgnoreRobots: true, NoSitemap: true},
+			want: []string{"GitHub:acme-inc", "Instagram:acme.press", "LinkedIn:acme-private", "TikTok:acmedeep", "Twitter:acme"},
+		},
+		{
+			name: "Page limit",
+			opts: CrawlOptions{MaxDepth: 3, MaxPages: 2, NoSitemap: true},
+			want: []string{"GitHub:acme-inc", "Twitter:acme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos, err := NewExtractor(tt.opts).ExtractSocialLinks(context.Background(), srv.URL)
+			if err != nil {
+				t.Fatalf("ExtractSocialLinks() error = %v", err)
+			}
+			got := usernames(infos)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ExtractSocialLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractor_CrawlRecordsPage(t *testing.T) {
+	srv := newSite(t, map[string]string{
+		"/":        `<a href="/about/">About</a><a href="/about">About again</a>`,
+		"/about/":  `<a href="https://twitter.com/acme">Twitter</a>`,
+		"/about":   `<a href="https://twitter.com/acme">Twitter</a>`,
+		"/unused/": ``,
+	})
+
+	infos, err := NewExtractor(CrawlOptions{MaxDepth: 1, MaxPages: 10}).ExtractSocialLinks(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("ExtractSocialLinks() error = %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("got %d links, want 1 (trailing slash variants are the same page): %+v", len(infos), infos)
+	}
+	if want := srv.URL + "/about/"; infos[0].Page != want {
+		t.Errorf("Page = %q, want %q", infos[0].Page, want)
+	}
+}
+
+func TestExtractor_CrawlStartPageError(t *testing.T) {
+	srv := newSite(t, map[string]string{})
+	_, err := NewExtractor(DefaultCrawlOptions()).ExtractSocialLinks(context.Background(), srv.URL)
+	var se *httpx.StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Errorf("ExtractSocialLinks() error = %v, want a 404 status error", err)
+	}
+}
+
+func TestRobots_Allowed(t *testing.T) {
+	txt := `
+User-agent: googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /admin
+Disallow: /*.php$
+Allow: /admin/public
+
+User-agent: SocialRecon
+User-agent: other
+Disallow: /secret
+`
+	tests := []struct {
+		agent string
+		path  string
+		want  bool
+	}{
+		{"somebot", "/", true},
+		{"somebot", "/admin/users", false},
+		{"somebot", "/admin/public/page", true},
+		{"somebot", "/index.php", false},
+		{"somebot", "/index.php?x=1", true},
+		{"socialrecon", "/admin/users", true},
+		{"socialrecon", "/secret/plans", false},
+	}
+
+	for _, tt := range tests {
+		r := parseRobots(strings.NewReader(txt), tt.agent)
+		if got := r.allowed(tt.path); got != tt.want {
+			t.Errorf("allowed(%q) for %s = %v, want %v", tt.path, tt.agent, got, tt.want)
+		}
+	}
+}
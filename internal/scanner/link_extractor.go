@@ -1,10 +1,13 @@
 package scanner
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,80 +16,306 @@ import (
 
 // Info represents a discovered social media indicator
 type Info struct {
-	Platform string
-	Username string
-	URL      string
+	Platform  string
+	Username  string
+	URL       string   // link as found, made absolute
+	Profile   string   // canonical profile URL
+	Page      string   // page the link was found on
+	Via       string   // how the link was extracted, one of the Via constants
+	Redirects []string // URLs the link led through to the profile, when it was a redirect or shortened link
+}
+
+// Extraction sources recorded in Info.Via
+const (
+	ViaAnchor       = "anchor"        // <a href>
+	ViaRelMe        = "rel-me"        // <link rel="me">
+	ViaMeta         = "meta"          // twitter:site, twitter:creator and og: tags
+	ViaJSONLD       = "json-ld"       // schema.org sameAs
+	ViaScript       = "script"        // URL in an inline script
+	ViaLinkedScript = "linked-script" // URL in a same-site script file
+)
+
+// scriptURL finds absolute and protocol-relative URLs in script source
+var scriptURL = regexp.MustCompile(`(?:https?:)?//[A-Za-z0-9.-]+\.[A-Za-z]{2,}(?:/[^\s"'<>()\x60\\]*)?`)
+
+// matchLink returns the profile a link points to, if any, resolved to its
+// canonical identity. The link itself is kept as found.
+func matchLink(link string) (Info, bool) {
+	id, ok := Resolve(link)
+	if !ok {
+		return Info{}, false
+	}
+	return Info{
+		Platform: id.Platform,
+		Username: id.Username,
+		URL:      strings.TrimSpace(link),
+		Profile:  id.URL,
+	}, true
+}
+
+// scriptLinks returns the absolute and protocol-relative URLs in JavaScript
+// source
+func scriptLinks(src string) []string {
+	// JSON embedded in scripts often escapes slashes
+	return scriptURL.FindAllString(strings.ReplaceAll(src, `\/`, "/"), -1)
 }
 
 // Extractor handles fetching and parsing social links
 type Extractor struct {
 	client *http.Client
+	opts   CrawlOptions
 }
 
-func NewExtractor() *Extractor {
+// NewExtractor creates an extractor that crawls sites within the given bounds
+func NewExtractor(opts CrawlOptions) *Extractor {
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = DefaultCrawlOptions().MaxPages
+	}
+	if opts.MaxDepth < 0 {
+		opts.MaxDepth = 0
+	}
 	return &Extractor{
 		client: &http.Client{
 			Timeout: 15 * time.Second,
 		},
+		opts: opts,
 	}
 }
 
-// ExtractSocialLinks scans a URL for social media profiles
-func (e *Extractor) ExtractSocialLinks(targetURL string) ([]Info, error) {
+// ExtractSocialLinks crawls a site, starting at targetURL, for social media
+// profiles. Only a failure to fetch the start page is returned as an error.
+func (e *Extractor) ExtractSocialLinks(ctx context.Context, targetURL string) ([]Info, error) {
 	if !strings.HasPrefix(targetURL, "http") {
 		targetURL = "http://" + targetURL
 	}
+	return e.crawl(ctx, targetURL)
+}
 
-	resp, err := e.client.Get(targetURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// page is what a single fetched HTML page yields
+type page struct {
+	url     *url.URL // final URL after redirects
+	chain   []string // URLs requested to reach the page, including meta refreshes
+	infos   []Info
+	links   []*url.URL // every resolvable link, for crawling
+	scripts []*url.URL // external scripts
+	short   []pending  // shortened links to expand
+	refresh *url.URL   // meta refresh target
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch URL: %s (status %d)", targetURL, resp.StatusCode)
-	}
+// pending is a shortened link waiting to be expanded
+type pending struct {
+	link *url.URL
+	page string
+	via  string
+}
 
-	return e.parseHTML(resp.Body), nil
+// found records a profile link seen on the page
+func (p *page) found(info Info, via string) {
+	info.Page = p.url.String()
+	info.Via = via
+	p.infos = append(p.infos, info)
 }
 
-func (e *Extractor) parseHTML(r io.Reader) []Info {
-	var infos []Info
+// link records a link to a profile, seeing through tracking redirects;
+// shortened links are kept for expansion
+func (p *page) link(u *url.URL, via string) {
+	if dest, ok := unwrap(u, p.url); ok {
+		if info, ok := matchLink(dest.String()); ok {
+			info.Redirects = []string{dest.String()}
+			info.URL = u.String()
+			p.found(info, via)
+			return
+		}
+	}
+	if info, ok := matchLink(u.String()); ok {
+		p.found(info, via)
+		return
+	}
+	if isShortener(u) {
+		p.short = append(p.short, pending{link: u, page: p.url.String(), via: via})
+	}
+}
+
+// parseHTML extracts the profile links of a page. Links are resolved
+// against the page URL, or the document's <base href>.
+func (e *Extractor) parseHTML(r io.Reader, pageURL *url.URL) *page {
+	p := &page{url: pageURL}
+	base := pageURL
 	z := html.NewTokenizer(r)
 
-	// Regex for social platforms
-	patterns := map[string]*regexp.Regexp{
-		"Twitter":   regexp.MustCompile(`(?:https?://)?(?:www\.)?twitter\.com/([a-zA-Z0-9_]{1,15})`),
-		"GitHub":    regexp.MustCompile(`(?:https?://)?(?:www\.)?github\.com/([a-zA-Z0-9-]{1,39})`),
-		"Instagram": regexp.MustCompile(`(?:https?://)?(?:www\.)?instagram\.com/([a-zA-Z0-9_\.]{1,30})`),
-		"TikTok":    regexp.MustCompile(`(?:https?://)?(?:www\.)?tiktok\.com/@([a-zA-Z0-9_\.]{2,24})`),
-		"LinkedIn":  regexp.MustCompile(`(?:https?://)?(?:www\.)?linkedin\.com/in/([a-zA-Z0-9-]{3,100})`),
+	// script is the kind of <script> being read: "" outside scripts
+	var script string
+	hasBase := false
+
+	// resolve makes a link absolute
+	resolve := func(link string) (*url.URL, bool) {
+		u, err := base.Parse(strings.TrimSpace(link))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, false
+		}
+		return u, true
 	}
 
 	for {
 		tt := z.Next()
 		switch tt {
 		case html.ErrorToken:
-			return infos
+			return p
+		case html.TextToken:
+			switch script {
+			case ViaJSONLD:
+				for _, link := range sameAs(z.Text()) {
+					if u, ok := resolve(link); ok {
+						p.link(u, ViaJSONLD)
+					}
+				}
+			case ViaScript:
+				for _, link := range scriptLinks(string(z.Text())) {
+					if u, ok := resolve(link); ok {
+						p.link(u, ViaScript)
+					}
+				}
+			}
+		case html.EndTagToken:
+			script = ""
 		case html.StartTagToken, html.SelfClosingTagToken:
 			t := z.Token()
-			if t.Data == "a" {
-				for _, a := range t.Attr {
-					if a.Key == "href" {
-						val := a.Val
-						for platform, re := range patterns {
-							matches := re.FindStringSubmatch(val)
-							if len(matches) > 1 {
-								infos = append(infos, Info{
-									Platform: platform,
-									Username: matches[1],
-									URL:      val,
-								})
-							}
+			switch t.Data {
+			case "base":
+				if href, ok := attr(t, "href"); ok && !hasBase {
+					if u, ok := resolve(href); ok {
+						base, hasBase = u, true
+					}
+				}
+			case "a":
+				if href, ok := attr(t, "href"); ok {
+					if u, ok := resolve(href); ok {
+						p.link(u, ViaAnchor)
+						p.links = append(p.links, u)
+					}
+				}
+			case "link":
+				rel, _ := attr(t, "rel")
+				href, _ := attr(t, "href")
+				if hasToken(rel, "me") {
+					if u, ok := resolve(href); ok {
+						p.link(u, ViaRelMe)
+					}
+				}
+			case "meta":
+				p.meta(t, resolve)
+			case "script":
+				if tt == html.SelfClosingTagToken {
+					break
+				}
+				typ, _ := attr(t, "type")
+				src, hasSrc := attr(t, "src")
+				switch {
+				case strings.EqualFold(strings.TrimSpace(typ), "application/ld+json"):
+					script = ViaJSONLD
+				case hasSrc:
+					if u, ok := resolve(src); ok {
+						p.scripts = append(p.scripts, u)
+					}
+				default:
+					script = ViaScript
+				}
+			}
+		}
+	}
+}
+
+// meta extracts profiles from twitter: and og: meta tags and records meta
+// refreshes
+func (p *page) meta(t html.Token, resolve func(string) (*url.URL, bool)) {
+	content, _ := attr(t, "content")
+	content = strings.TrimSpace(content)
+
+	if equiv, _ := attr(t, "http-equiv"); strings.EqualFold(strings.TrimSpace(equiv), "refresh") {
+		if u, ok := parseRefresh(content, p.url); ok && p.refresh == nil {
+			p.refresh = u
+		}
+		return
+	}
+
+	name, ok := attr(t, "property")
+	if !ok {
+		name, _ = attr(t, "name")
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	switch {
+	case name == "twitter:site" || name == "twitter:creator":
+		// Usually a bare handle, sometimes a profile URL
+		if handle, ok := strings.CutPrefix(content, "@"); ok {
+			content = "https://twitter.com/" + handle
+		}
+	case strings.HasPrefix(name, "og:"):
+	default:
+		return
+	}
+	if u, ok := resolve(content); ok {
+		p.link(u, ViaMeta)
+	}
+}
+
+// sameAs returns the schema.org sameAs URLs of a JSON-LD document, wherever
+// they appear in it
+func sameAs(data []byte) []string {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var links []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if k != "sameAs" {
+					walk(child)
+					continue
+				}
+				switch s := child.(type) {
+				case string:
+					links = append(links, s)
+				case []interface{}:
+					for _, item := range s {
+						if str, ok := item.(string); ok {
+							links = append(links, str)
 						}
 					}
 				}
 			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	sort.Strings(links)
+	return links
+}
+
+// attr returns the value of a tag attribute
+func attr(t html.Token, key string) (string, bool) {
+	for _, a := range t.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// hasToken reports whether a space-separated attribute value such as rel
+// contains token
+func hasToken(value, token string) bool {
+	for _, f := range strings.Fields(value) {
+		if strings.EqualFold(f, token) {
+			return true
 		}
 	}
+	return false
 }
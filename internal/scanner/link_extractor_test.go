@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestExtractor_ParseHTML(t *testing.T) {
+	doc := `<html><head>
+		<meta name="twitter:site" content="@acme">
+		<meta name="twitter:creator" content="https://twitter.com/acme_ceo">
+		<meta property="og:see_also" content="https://www.instagram.com/acme.photos/">
+		<meta property="og:title" content="https://github.com/acme-og">
+		<meta name="description" content="https://twitter.com/ignored">
+		<link rel="me authn" href="https://github.com/acme-inc">
+		<link rel="stylesheet" href="https://github.com/styles">
+		<script type="application/ld+json">
+		{"@context": "https://schema.org", "@graph": [{"@type": "Organization",
+		 "sameAs": ["https://www.linkedin.com/in/acme-corp", "https://example.org/x"]}]}
+		</script>
+		<script>window.cfg = {"tiktok":"https:\/\/www.tiktok.com\/@acme.tok", "cdn": "https://platform.twitter.com/widgets.js"};</script>
+		<script src="/static/app.js"></script>
+		</head><body>
+		<a href="https://twitter.com/acme_help">Help</a>
+		<a href="https://example.com/?next=https://twitter.com/notme">Redirect</a>
+		</body></html>`
+
+	base, _ := url.Parse("https://acme.example/")
+	p := NewExtractor(DefaultCrawlOptions()).parseHTML(strings.NewReader(doc), base)
+
+	want := map[string]string{
+		"Twitter:acme":          ViaMeta,
+		"Twitter:acme_ceo":      ViaMeta,
+		"Instagram:acme.photos": ViaMeta,
+		"GitHub:acme-og":        ViaMeta,
+		"GitHub:acme-inc":       ViaRelMe,
+		"LinkedIn:acme-corp":    ViaJSONLD,
+		"TikTok:acme.tok":       ViaScript,
+		"Twitter:acme_help":     ViaAnchor,
+	}
+
+	got := make(map[string]string)
+	for _, info := range p.infos {
+		got[info.Platform+":"+info.Username] = info.Via
+		if info.Page != base.String() {
+			t.Errorf("%s: Page = %q, want %q", info.Username, info.Page, base)
+		}
+	}
+	for k, via := range want {
+		if got[k] != via {
+			t.Errorf("%s: Via = %q, want %q", k, got[k], via)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if len(p.scripts) != 1 || p.scripts[0].String() != "https://acme.example/static/app.js" {
+		t.Errorf("scripts = %v, want the resolved app.js", p.scripts)
+	}
+}
+
+func TestExtractor_CrawlLinkedScripts(t *testing.T) {
+	srv := newSite(t, map[string]string{
+		"/":              `<script src="/static/app.js"></script><script src="https://cdn.example.net/lib.js"></script>`,
+		"/about":         `<script src="/static/app.js"></script>`,
+		"/static/app.js": `const links = ["https://github.com/acme-js", "//instagram.com/acme.js"];`,
+	})
+
+	infos, err := NewExtractor(DefaultCrawlOptions()).ExtractSocialLinks(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("ExtractSocialLinks() error = %v", err)
+	}
+	got := usernames(infos)
+	if want := "GitHub:acme-js,Instagram:acme.js"; strings.Join(got, ",") != want {
+		t.Fatalf("got %v, want %s", got, want)
+	}
+	for _, info := range infos {
+		if info.Via != ViaLinkedScript || info.Page != srv.URL+"/static/app.js" {
+			t.Errorf("%s: Via = %q Page = %q", info.Username, info.Via, info.Page)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Platform is the handle model of a social platform: where its profiles
+// live, which handles can exist, which profile-looking paths belong to the
+// platform itself, and how handles are compared
+type Platform struct {
+	Name          string
+	Domains       []string        // domains serving profiles, the first is canonical
+	Profiles      []string        // profile URL shapes, {username} is substituted; the first is canonical
+	Pattern       *regexp.Regexp  // handles that can exist
+	Reserved      map[string]bool // lowercase first path segments that are not profiles
+	CaseSensitive bool            // handles that differ by case are different accounts
+
+	noEdge   string         // characters a handle may not start or end with
+	noRepeat string         // characters that may not appear twice in a row
+	ids      *regexp.Regexp // opaque account IDs, which keep their case
+}
+
+// HandleError explains why a handle cannot be a profile on a platform
+type HandleError struct {
+	Platform string
+	Handle   string
+	Reserved bool
+}
+
+func (e *HandleError) Error() string {
+	if e.Reserved {
+		return fmt.Sprintf("'%s' is a reserved %s path, not a profile", e.Handle, e.Platform)
+	}
+	return fmt.Sprintf("'%s' is not a valid %s username", e.Handle, e.Platform)
+}
+
+// Validate returns a *HandleError if handle cannot be a profile on p
+func (p *Platform) Validate(handle string) error {
+	if p.Reserved[strings.ToLower(handle)] {
+		return &HandleError{Platform: p.Name, Handle: handle, Reserved: true}
+	}
+	valid := p.Pattern.MatchString(handle)
+	if valid && p.noEdge != "" {
+		valid = !strings.ContainsAny(handle[:1], p.noEdge) && !strings.ContainsAny(handle[len(handle)-1:], p.noEdge)
+	}
+	for _, c := range p.noRepeat {
+		if valid && strings.Contains(handle, string(c)+string(c)) {
+			valid = false
+		}
+	}
+	if !valid {
+		return &HandleError{Platform: p.Name, Handle: handle}
+	}
+	return nil
+}
+
+// Normalize returns the form handles are compared and scanned in
+func (p *Platform) Normalize(handle string) string {
+	if p.CaseSensitive || p.ids != nil && p.ids.MatchString(handle) {
+		return handle
+	}
+	return strings.ToLower(handle)
+}
+
+// LookupPlatform returns the model of a platform by name, case-insensitively,
+// or nil if the platform is not known
+func LookupPlatform(name string) *Platform {
+	for _, p := range platforms {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// ValidateHandle checks a handle against the model of the named platform.
+// Handles of unknown platforms are always accepted.
+func ValidateHandle(platform, handle string) error {
+	if p := LookupPlatform(platform); p != nil {
+		return p.Validate(handle)
+	}
+	return nil
+}
+
+func reserved(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// platforms holds the handle models of the supported platforms
+var platforms = []*Platform{
+	{
+		Name:     "Twitter",
+		Domains:  []string{"twitter.com", "x.com"},
+		Profiles: []string{"https://twitter.com/{username}"},
+		Pattern:  regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`),
+		Reserved: reserved(
+			"about", "account", "compose", "download", "explore", "hashtag", "help",
+			"home", "i", "intent", "jobs", "login", "logout", "messages", "notifications",
+			"oauth", "privacy", "search", "settings", "share", "signup", "tos",
+			"widgets", "who_to_follow",
+		),
+	},
+	{
+		Name:     "GitHub",
+		Domains:  []string{"github.com"},
+		Profiles: []string{"https://github.com/{username}"},
+		Pattern:  regexp.MustCompile(`^[A-Za-z0-9-]{1,39}$`),
+		Reserved: reserved(
+			"about", "account", "apps", "blog", "codespaces", "collections", "contact",
+			"customer-stories", "dashboard", "enterprise", "events", "explore",
+			"features", "issues", "join", "login", "logout", "marketplace", "new",
+			"notifications", "orgs", "organizations", "pricing", "pulls", "readme",
+			"search", "security", "settings", "signup", "site", "sponsors", "stars",
+			"team", "topics", "trending",
+		),
+		noEdge:   "-",
+		noRepeat: "-",
+	},
+	{
+		Name:     "Instagram",
+		Domains:  []string{"instagram.com", "instagr.am"},
+		Profiles: []string{"https://www.instagram.com/{username}/"},
+		Pattern:  regexp.MustCompile(`^[A-Za-z0-9_.]{1,30}$`),
+		Reserved: reserved(
+			"about", "accounts", "api", "challenge", "developer", "direct", "emails",
+			"explore", "legal", "oauth", "p", "press", "privacy", "reel", "reels",
+			"static", "stories", "terms", "tv", "web",
+		),
+		noEdge:   ".",
+		noRepeat: ".",
+	},
+	{
+		Name:     "TikTok",
+		Domains:  []string{"tiktok.com"},
+		Profiles: []string{"https://www.tiktok.com/@{username}"},
+		Pattern:  regexp.MustCompile(`^[A-Za-z0-9_.]{2,24}$`),
+		noEdge:   ".",
+	},
+	{
+		Name:    "LinkedIn",
+		Domains: []string{"linkedin.com"},
+		Profiles: []string{
+			"https://www.linkedin.com/in/{username}",
+			"https://www.linkedin.com/company/{username}",
+		},
+		Pattern: regexp.MustCompile(`^[A-Za-z0-9-]{3,100}$`),
+	},
+	{
+		Name:    "Facebook",
+		Domains: []string{"facebook.com", "fb.com", "fb.me"},
+		Profiles: []string{
+			"https://www.facebook.com/{username}",
+			"https://www.facebook.com/profile.php?id={username}",
+		},
+		Pattern: regexp.MustCompile(`^[A-Za-z0-9.]{5,50}$`),
+		Reserved: reserved(
+			"business", "dialog", "events", "gaming", "groups", "hashtag", "help",
+			"home.php", "l.php", "legal", "login", "marketplace", "media", "messages",
+			"notes", "pages", "people", "permalink.php", "photo.php", "plugins",
+			"policies", "privacy", "profile.php", "public", "settings", "share",
+			"sharer", "sharer.php", "story.php", "watch",
+		),
+		noEdge: ".",
+	},
+	{
+		// youtu.be links are videos; they identify the platform but no channel
+		Name:    "YouTube",
+		Domains: []string{"youtube.com", "youtu.be"},
+		Profiles: []string{
+			"https://www.youtube.com/@{username}",
+			"https://www.youtube.com/c/{username}",
+			"https://www.youtube.com/user/{username}",
+			"https://www.youtube.com/channel/{username}",
+		},
+		Pattern: regexp.MustCompile(`^[A-Za-z0-9_.-]{3,30}$`),
+		ids:     regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`),
+	},
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlatform_Validate(t *testing.T) {
+	tests := []struct {
+		platform string
+		handle   string
+		valid    bool
+		reserved bool
+	}{
+		{platform: "Twitter", handle: "acme_corp", valid: true},
+		{platform: "Twitter", handle: "share", reserved: true},
+		{platform: "Twitter", handle: "I", reserved: true},
+		{platform: "Twitter", handle: "acme-corp"},
+		{platform: "Twitter", handle: "a_very_long_handle"},
+		{platform: "GitHub", handle: "acme-inc", valid: true},
+		{platform: "GitHub", handle: "Features", reserved: true},
+		{platform: "GitHub", handle: "acme--inc"},
+		{platform: "GitHub", handle: "acme-"},
+		{platform: "Instagram", handle: "acme.photos", valid: true},
+		{platform: "Instagram", handle: "p", reserved: true},
+		{platform: "Instagram", handle: ".acme"},
+		{platform: "Instagram", handle: "acme..photos"},
+		{platform: "TikTok", handle: "a"},
+		{platform: "LinkedIn", handle: "acme-corp", valid: true},
+		{platform: "Mastodon", handle: "anything goes", valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform+"/"+tt.handle, func(t *testing.T) {
+			err := ValidateHandle(tt.platform, tt.handle)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ValidateHandle() error = %v, want valid %v", err, tt.valid)
+			}
+			var he *HandleError
+			if err != nil && (!errors.As(err, &he) || he.Reserved != tt.reserved) {
+				t.Errorf("ValidateHandle() error = %#v, want reserved %v", err, tt.reserved)
+			}
+		})
+	}
+}
+
+func TestMatchLink_NonProfiles(t *testing.T) {
+	tests := []struct {
+		link string
+		want string // platform:username, empty when not a profile
+	}{
+		{link: "https://twitter.com/AcmeCorp", want: "Twitter:acmecorp"},
+		{link: "https://twitter.com/share?url=https://acme.example", want: ""},
+		{link: "https://twitter.com/intent/tweet", want: ""},
+		{link: "https://twitter.com/a_handle_that_is_too_long", want: ""},
+		{link: "https://github.com/features/actions", want: ""},
+		{link: "https://github.com/acme-inc/repo", want: "GitHub:acme-inc"},
+		{link: "https://www.instagram.com/p/Cabc123/", want: ""},
+		{link: "https://www.instagram.com/acme.photos/", want: "Instagram:acme.photos"},
+		{link: "https://www.linkedin.com/in/", want: ""},
+		{link: "https://example.com/?u=https://twitter.com/notme", want: ""},
+	}
+
+	for _, tt := range tests {
+		info, ok := matchLink(tt.link)
+		got := ""
+		if ok {
+			got = info.Platform + ":" + info.Username
+		}
+		if got != tt.want {
+			t.Errorf("matchLink(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
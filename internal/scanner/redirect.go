@@ -0,0 +1,153 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxRedirects bounds the hops followed when expanding a link or following
+// meta refreshes
+const maxRedirects = 5
+
+// shorteners are hosts whose links only redirect elsewhere; they are
+// expanded over the network when CrawlOptions.ExpandLinks is set
+var shorteners = map[string]bool{
+	"bit.ly": true, "bitly.com": true, "buff.ly": true, "cutt.ly": true,
+	"goo.gl": true, "is.gd": true, "lnkd.in": true, "ow.ly": true,
+	"rebrand.ly": true, "shorturl.at": true, "t.co": true, "t.ly": true,
+	"tiny.cc": true, "tinyurl.com": true, "trib.al": true,
+}
+
+// redirectors are tracking redirect endpoints that carry their destination
+// in a query parameter, so they can be unwrapped without a request
+var redirectors = []struct {
+	host  string
+	path  string // path prefix, empty for any
+	param string
+}{
+	{"l.facebook.com", "/l.php", "u"},
+	{"lm.facebook.com", "/l.php", "u"},
+	{"l.instagram.com", "", "u"},
+	{"www.google.com", "/url", "q"},
+	{"www.google.com", "/url", "url"},
+	{"google.com", "/url", "q"},
+	{"www.youtube.com", "/redirect", "q"},
+	{"out.reddit.com", "", "url"},
+	{"t.umblr.com", "/redirect", "z"},
+	{"away.vk.com", "/away.php", "to"},
+	{"www.linkedin.com", "/redir/redirect", "url"},
+}
+
+// redirectParams are query parameters a site's own redirect links commonly
+// carry their destination in
+var redirectParams = []string{"url", "u", "to", "target", "dest", "destination", "redirect", "redirect_uri", "goto", "link", "out"}
+
+// unwrap returns the destination of a tracking redirect link. Known
+// redirectors are unwrapped by their parameter; links on the page's own site
+// are unwrapped only when a parameter points to a profile, so a site's
+// share buttons are not mistaken for its profiles.
+func unwrap(u, page *url.URL) (*url.URL, bool) {
+	host := strings.ToLower(u.Hostname())
+	q := u.Query()
+	for _, r := range redirectors {
+		if host == r.host && strings.HasPrefix(u.Path, r.path) {
+			if dest, err := url.Parse(q.Get(r.param)); err == nil && dest.Host != "" {
+				return dest, true
+			}
+		}
+	}
+	if page == nil || !sameSite(u, page) {
+		return nil, false
+	}
+	for _, p := range redirectParams {
+		v := q.Get(p)
+		if v == "" {
+			continue
+		}
+		if _, ok := Resolve(v); ok {
+			if dest, err := url.Parse(v); err == nil {
+				return dest, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// isShortener reports whether a link points to a known link shortener
+func isShortener(u *url.URL) bool {
+	return shorteners[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")]
+}
+
+// expand follows the redirects of a shortened link one hop at a time until
+// it reaches a profile or a page that does not redirect. It returns the
+// chain of URLs visited after the link itself.
+func (e *Extractor) expand(ctx context.Context, link *url.URL) ([]string, error) {
+	client := *e.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	var chain []string
+	current := link
+	for hop := 0; hop < maxRedirects; hop++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, current.String(), nil)
+		if err != nil {
+			return chain, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		resp, err := client.Do(req)
+		if err != nil {
+			return chain, err
+		}
+		resp.Body.Close()
+
+		loc := resp.Header.Get("Location")
+		if resp.StatusCode < 300 || resp.StatusCode >= 400 || loc == "" {
+			return chain, nil
+		}
+		next, err := current.Parse(loc)
+		if err != nil {
+			return chain, err
+		}
+		if dest, ok := unwrap(next, nil); ok {
+			next = dest
+		}
+		chain = append(chain, next.String())
+		if _, ok := Resolve(next.String()); ok || !isShortener(next) && LookupHost(next.Hostname()) != nil {
+			// A profile, or another page of a platform: no need to load it
+			return chain, nil
+		}
+		current = next
+	}
+	return chain, errors.New("too many redirects")
+}
+
+// refreshURL matches the target of a meta refresh, e.g. "0; url='/home'"
+var refreshURL = regexp.MustCompile(`(?i)^\s*\d+(?:\.\d*)?\s*[;,]\s*(?:url\s*=\s*)?['"]?([^'"]+?)['"]?\s*$`)
+
+// parseRefresh returns the target of a meta refresh content attribute
+func parseRefresh(content string, base *url.URL) (*url.URL, bool) {
+	m := refreshURL.FindStringSubmatch(content)
+	if m == nil || strings.TrimSpace(m[1]) == "" {
+		return nil, false
+	}
+	u, err := base.Parse(strings.TrimSpace(m[1]))
+	if err != nil {
+		return nil, false
+	}
+	return u, true
+}
+
+// redirectChain returns the URLs of an HTTP redirect chain ending in resp,
+// in the order they were requested
+func redirectChain(resp *http.Response) []string {
+	var chain []string
+	for r := resp; r != nil && r.Request != nil; r = r.Request.Response {
+		chain = append([]string{r.Request.URL.String()}, chain...)
+	}
+	return chain
+}
@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractor_ParseHTMLRelativeAndRedirectLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want []string
+	}{
+		{
+			name: "Protocol-relative link",
+			html: `<a href="//twitter.com/acme">Twitter</a>`,
+			want: []string{"Twitter:acme"},
+		},
+		{
+			name: "Base href",
+			html: `<base href="https://github.com/"><a href="acme-inc">GitHub</a>`,
+			want: []string{"GitHub:acme-inc"},
+		},
+		{
+			name: "Facebook link shim",
+			html: `<a href="https://l.facebook.com/l.php?u=https%3A%2F%2Finstagram.com%2Facme.photos&h=x">IG</a>`,
+			want: []string{"Instagram:acme.photos"},
+		},
+		{
+			name: "Same-site redirect",
+			html: `<a href="/out?url=https%3A%2F%2Ftwitter.com%2Facme">Twitter</a>`,
+			want: []string{"Twitter:acme"},
+		},
+		{
+			name: "Same-site share button",
+			html: `<a href="/share?url=https%3A%2F%2Facme.example%2Fpost">Share</a>`,
+		},
+	}
+
+	base, _ := url.Parse("https://acme.example/blog/")
+	e := NewExtractor(DefaultCrawlOptions())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := usernames(e.parseHTML(strings.NewReader(tt.html), base).infos)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHTML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractor_UnwrappedLinkRecordsRedirect(t *testing.T) {
+	base, _ := url.Parse("https://acme.example/")
+	p := NewExtractor(DefaultCrawlOptions()).parseHTML(strings.NewReader(
+		`<a href="/go?to=https://twitter.com/acme">Twitter</a>`), base)
+	if len(p.infos) != 1 {
+		t.Fatalf("parseHTML() found %d links, want 1", len(p.infos))
+	}
+	info := p.infos[0]
+	if info.URL != "https://acme.example/go?to=https://twitter.com/acme" {
+		t.Errorf("URL = %q, want the link as found", info.URL)
+	}
+	if want := []string{"https://twitter.com/acme"}; !reflect.DeepEqual(info.Redirects, want) {
+		t.Errorf("Redirects = %v, want %v", info.Redirects, want)
+	}
+}
+
+func TestExtractor_CrawlFollowsMetaRefresh(t *testing.T) {
+	srv := newSite(t, map[string]string{
+		"/":     `<meta http-equiv="refresh" content="0; url='/home'">`,
+		"/home": `<a href="https://twitter.com/acme">Twitter</a>`,
+	})
+
+	infos, err := NewExtractor(CrawlOptions{MaxDepth: 0, MaxPages: 5, NoSitemap: true}).
+		ExtractSocialLinks(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("ExtractSocialLinks() error = %v", err)
+	}
+	if got := usernames(infos); !reflect.DeepEqual(got, []string{"Twitter:acme"}) {
+		t.Fatalf("ExtractSocialLinks() = %v, want [Twitter:acme]", got)
+	}
+	if infos[0].Page != srv.URL+"/home" {
+		t.Errorf("Page = %q, want %q", infos[0].Page, srv.URL+"/home")
+	}
+}
+
+func TestExtractor_Expand(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/abc":
+			http.Redirect(w, r, srv.URL+"/track?id=1", http.StatusMovedPermanently)
+		case "/track":
+			http.Redirect(w, r, "https://twitter.com/AcmeCorp", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.Write([]byte("not a redirect"))
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Chain to profile",
+			path: "/abc",
+			want: []string{srv.URL + "/track?id=1", "https://twitter.com/AcmeCorp"},
+		},
+		{
+			name: "Not a redirect",
+			path: "/page",
+		},
+		{
+			name:    "Redirect loop",
+			path:    "/loop",
+			wantErr: true,
+		},
+	}
+
+	e := NewExtractor(DefaultCrawlOptions())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, _ := url.Parse(srv.URL + tt.path)
+			chain, err := e.expand(context.Background(), link)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(chain, tt.want) {
+				t.Errorf("expand() = %v, want %v", chain, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRefresh(t *testing.T) {
+	base, _ := url.Parse("https://acme.example/a/")
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{content: "0; url=/home", want: "https://acme.example/home"},
+		{content: "5;URL='next'", want: "https://acme.example/a/next"},
+		{content: `0, "https://twitter.com/acme"`, want: "https://twitter.com/acme"},
+		{content: "30"},
+		{content: "0;"},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if u, ok := parseRefresh(tt.content, base); ok {
+			got = u.String()
+		}
+		if got != tt.want {
+			t.Errorf("parseRefresh(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
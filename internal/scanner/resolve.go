@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Identity is the canonical reference to a profile: the same account linked
+// through x.com, mobile.twitter.com or twitter.com resolves to one Identity
+type Identity struct {
+	Platform string
+	Username string // normalized handle
+	URL      string // canonical profile URL
+}
+
+// hostPrefixes are the subdomains under which platforms serve the same
+// profiles as on their bare domain
+var hostPrefixes = map[string]bool{"www": true, "m": true, "mobile": true, "web": true}
+
+// localePrefix matches locale subdomains such as fr., de-de. or es-la.
+var localePrefix = regexp.MustCompile(`^[a-z]{2}(?:-[a-z]{2,4})?$`)
+
+// LookupHost returns the platform serving profiles on host, accepting every
+// domain alias and mobile or locale subdomain, or nil if the host is not a
+// known platform
+func LookupHost(host string) *Platform {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, p := range platforms {
+		for _, d := range p.Domains {
+			if host == d {
+				return p
+			}
+			sub, ok := strings.CutSuffix(host, "."+d)
+			if ok && (hostPrefixes[sub] || localePrefix.MatchString(sub)) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve maps a profile link on any known alias of a platform to its
+// canonical identity. Links without a scheme and protocol-relative links are
+// accepted. Links to platform pages that are not profiles, or to handles the
+// platform cannot have, do not resolve.
+func Resolve(link string) (Identity, bool) {
+	u, ok := parseLink(link)
+	if !ok {
+		return Identity{}, false
+	}
+	p := LookupHost(u.Hostname())
+	if p == nil {
+		return Identity{}, false
+	}
+	for _, tmpl := range p.Profiles {
+		handle, ok := handleFromURL(tmpl, u)
+		if !ok || p.Validate(handle) != nil {
+			continue
+		}
+		handle = p.Normalize(handle)
+		return Identity{Platform: p.Name, Username: handle, URL: expandProfile(tmpl, handle)}, true
+	}
+	return Identity{}, false
+}
+
+// ProfileURL returns the canonical profile URL of a handle on p
+func (p *Platform) ProfileURL(handle string) string {
+	return expandProfile(p.Profiles[0], handle)
+}
+
+func expandProfile(tmpl, handle string) string {
+	return strings.ReplaceAll(tmpl, "{username}", url.PathEscape(handle))
+}
+
+// handleFromURL extracts the handle from a link with the shape of a profile
+// URL template: its path, e.g. /in/{username}, or a query parameter, e.g.
+// /profile.php?id={username}
+func handleFromURL(tmpl string, u *url.URL) (string, bool) {
+	t, err := url.Parse(tmpl)
+	if err != nil {
+		return "", false
+	}
+	for key, values := range t.Query() {
+		if len(values) == 1 && values[0] == "{username}" {
+			if !strings.EqualFold(strings.TrimSuffix(u.Path, "/"), t.Path) {
+				return "", false
+			}
+			handle := u.Query().Get(key)
+			return handle, handle != ""
+		}
+	}
+	prefix, _, _ := strings.Cut(t.Path, "{username}")
+
+	rest, ok := cutPrefixFold(u.Path, prefix)
+	if !ok {
+		return "", false
+	}
+	handle, _, _ := strings.Cut(rest, "/")
+	return handle, handle != ""
+}
+
+// parseLink parses an absolute, scheme-less or protocol-relative link
+func parseLink(link string) (*url.URL, bool) {
+	link = strings.TrimSpace(link)
+	switch {
+	case strings.HasPrefix(link, "//"):
+		link = "https:" + link
+	case !hasPrefixFold(link, "http://") && !hasPrefixFold(link, "https://"):
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if !hasPrefixFold(s, prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
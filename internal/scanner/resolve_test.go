@@ -0,0 +1,64 @@
+package scanner
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		link string
+		want Identity
+		ok   bool
+	}{
+		{link: "https://twitter.com/Acme", want: Identity{"Twitter", "acme", "https://twitter.com/acme"}, ok: true},
+		{link: "https://x.com/acme/status/123", want: Identity{"Twitter", "acme", "https://twitter.com/acme"}, ok: true},
+		{link: "//mobile.twitter.com/acme", want: Identity{"Twitter", "acme", "https://twitter.com/acme"}, ok: true},
+		{link: "x.com/acme", want: Identity{"Twitter", "acme", "https://twitter.com/acme"}, ok: true},
+		{link: "https://x.com/home", ok: false},
+		{link: "https://instagr.am/acme.photos", want: Identity{"Instagram", "acme.photos", "https://www.instagram.com/acme.photos/"}, ok: true},
+		{link: "https://m.facebook.com/AcmeCorp/", want: Identity{"Facebook", "acmecorp", "https://www.facebook.com/acmecorp"}, ok: true},
+		{link: "https://fr-fr.facebook.com/acmecorp", want: Identity{"Facebook", "acmecorp", "https://www.facebook.com/acmecorp"}, ok: true},
+		{link: "https://www.facebook.com/sharer.php?u=x", ok: false},
+		{link: "https://m.facebook.com/profile.php?id=100064812345678", want: Identity{"Facebook", "100064812345678", "https://www.facebook.com/profile.php?id=100064812345678"}, ok: true},
+		{link: "https://www.facebook.com/profile.php", ok: false},
+		{link: "https://uk.linkedin.com/in/acme-corp", want: Identity{"LinkedIn", "acme-corp", "https://www.linkedin.com/in/acme-corp"}, ok: true},
+		{link: "https://www.linkedin.com/company/acme/about/", want: Identity{"LinkedIn", "acme", "https://www.linkedin.com/company/acme"}, ok: true},
+		{link: "https://www.linkedin.com/feed/", ok: false},
+		{link: "https://www.youtube.com/@AcmeTV/videos", want: Identity{"YouTube", "acmetv", "https://www.youtube.com/@acmetv"}, ok: true},
+		{link: "https://www.youtube.com/c/AcmeTV", want: Identity{"YouTube", "acmetv", "https://www.youtube.com/c/acmetv"}, ok: true},
+		{link: "https://www.youtube.com/user/acmevideos", want: Identity{"YouTube", "acmevideos", "https://www.youtube.com/user/acmevideos"}, ok: true},
+		{link: "https://www.youtube.com/channel/UCBR8-60-B28hp2BmDPdntcQ", want: Identity{"YouTube", "UCBR8-60-B28hp2BmDPdntcQ", "https://www.youtube.com/channel/UCBR8-60-B28hp2BmDPdntcQ"}, ok: true},
+		{link: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", ok: false},
+		{link: "https://youtu.be/dQw4w9WgXcQ", ok: false},
+		{link: "https://vm.tiktok.com/ZMabc/", ok: false},
+		{link: "https://www.tiktok.com/@acme.tok?lang=en", want: Identity{"TikTok", "acme.tok", "https://www.tiktok.com/@acme.tok"}, ok: true},
+		{link: "https://api.github.com/acme", ok: false},
+		{link: "https://evil-twitter.com/acme", ok: false},
+		{link: "mailto:acme@twitter.com", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Resolve(tt.link)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Resolve(%q) = %+v, %v; want %+v, %v", tt.link, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLookupHost(t *testing.T) {
+	tests := map[string]string{
+		"youtu.be":        "YouTube",
+		"WWW.X.COM":       "Twitter",
+		"de.linkedin.com": "LinkedIn",
+		"fb.me":           "Facebook",
+		"gist.github.com": "",
+		"example.com":     "",
+	}
+	for host, want := range tests {
+		got := ""
+		if p := LookupHost(host); p != nil {
+			got = p.Name
+		}
+		if got != want {
+			t.Errorf("LookupHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
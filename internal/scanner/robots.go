@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// robots holds the robots.txt rules that apply to the crawler and the
+// sitemaps the file declares
+type robots struct {
+	rules    []robotsRule
+	sitemaps []string
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// parseRobots reads a robots.txt. The rules of the groups naming agent are
+// used if there are any, otherwise those of the "*" groups.
+func parseRobots(r io.Reader, agent string) *robots {
+	var (
+		res      = &robots{}
+		specific []robotsRule
+		generic  []robotsRule
+		matched  bool // a group for agent exists
+		// agents of the current group; a new group starts with the first
+		// User-agent line after a rule
+		isAgent, isGeneric, inRules bool
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				isAgent, isGeneric, inRules = false, false, false
+			}
+			ua := strings.ToLower(value)
+			if ua == "*" {
+				isGeneric = true
+			} else if strings.HasPrefix(ua, agent) {
+				isAgent = true
+				matched = true
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				// "Disallow:" with no path allows everything
+				continue
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value}
+			if isAgent {
+				specific = append(specific, rule)
+			}
+			if isGeneric {
+				generic = append(generic, rule)
+			}
+		case "sitemap":
+			if value != "" {
+				res.sitemaps = append(res.sitemaps, value)
+			}
+		}
+	}
+
+	if matched {
+		res.rules = specific
+	} else {
+		res.rules = generic
+	}
+	return res
+}
+
+// allowed reports whether a path may be fetched. The longest matching rule
+// wins; on a tie allow wins.
+func (r *robots) allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || n == best && rule.allow {
+			best, allow = n, rule.allow
+		}
+	}
+	return allow
+}
+
+// robotsMatch matches a path against a robots.txt pattern, which is a path
+// prefix that may contain * wildcards and end with $ to anchor it
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for _, part := range parts[1:] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	if anchored && rest != "" {
+		// The last literal must end the path; retry it against the suffix
+		last := parts[len(parts)-1]
+		return len(parts) > 1 && strings.HasSuffix(path, last)
+	}
+	return true
+}
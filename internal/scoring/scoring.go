@@ -2,56 +2,93 @@ package scoring
 
 import (
 	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/similarity"
 )
 
+// largeAudience is the follower count from which a lookalike account is
+// considered able to mislead many people
+const largeAudience = 10000
+
 // ScoringEngine calculates risk scores based on findings
 type ScoringEngine struct {
 	weights map[string]float64
+	// critical indicators are exploitable as reported, not merely a risk
+	critical map[string]bool
+	// lookalike indicators are about handles imitating the target, where an
+	// existing account is the risk, rated at the given severity, and an
+	// available one is not
+	lookalike map[string]models.Severity
 }
 
 func NewScoringEngine() *ScoringEngine {
 	return &ScoringEngine{
 		weights: map[string]float64{
-			"github_profile":    10.0,
-			"twitter_profile":   15.0,
-			"instagram_profile": 12.0,
+			"github_profile":          10.0,
+			"twitter_profile":         15.0,
+			"instagram_profile":       12.0,
+			"broken_social_link":      25.0,
+			"impersonation_candidate": 8.0,
+			"homoglyph_impersonation": 15.0,
+		},
+		critical: map[string]bool{
+			"broken_social_link": true,
+		},
+		lookalike: map[string]models.Severity{
+			"impersonation_candidate": models.SeverityMedium,
+			"homoglyph_impersonation": models.SeverityHigh,
 		},
 	}
 }
 
 // Calculate assigns a cumulative risk score to the result
 func (e *ScoringEngine) Calculate(result *models.ScanResult) float64 {
-	if result == nil || len(result.Findings) == 0 {
+	if result == nil || result.FindingCount() == 0 {
 		return 0
 	}
 
 	var totalScore float64
 
-	for i := range result.Findings {
-		finding := &result.Findings[i]
+	result.EachFinding(func(finding *models.Finding) {
 		weight, ok := e.weights[finding.Indicator]
 		if !ok {
 			weight = 5.0 // default weight for unknown indicators
 		}
 
+		if _, ok := e.lookalike[finding.Indicator]; ok {
+			totalScore += e.lookalikeScore(finding, weight)
+			return
+		}
+
 		// Adjust weight based on status
 		switch finding.Status {
-		case "available":
+		case models.StatusAvailable:
 			// Hijack risk is higher than existence
 			totalScore += weight * 2.0
 			finding.Severity = models.SeverityHigh
-		case "suspended":
+			if e.critical[finding.Indicator] {
+				finding.Severity = models.SeverityCritical
+			}
+		case models.StatusSuspended, models.StatusDeactivated, models.StatusRestricted:
 			totalScore += weight * 0.5
 			finding.Severity = models.SeverityMedium
-		case "exists":
+		case models.StatusAbandoned:
+			// An official account nobody watches can be taken over or
+			// impersonated without its owner noticing
+			totalScore += weight
+			finding.Severity = models.SeverityMedium
+		case models.StatusExists, models.StatusPrivate:
 			totalScore += weight * 0.2
 			finding.Severity = models.SeverityInfo
+		case models.StatusUnknown, models.StatusRateLimited, models.StatusError, models.StatusInvalidUsername:
+			// Nothing is known about the account, so it carries no risk
+			// of its own; inconclusive checks are reported separately
+			finding.Severity = models.SeverityInfo
 		default:
 			// Handle unknown status gracefully
 			totalScore += weight * 0.1
 			finding.Severity = models.SeverityInfo
 		}
-	}
+	})
 
 	// Normalize score to 0-100 (cap at 100)
 	if totalScore > 100 {
@@ -61,6 +98,53 @@ func (e *ScoringEngine) Calculate(result *models.ScanResult) float64 {
 	return totalScore
 }
 
+// lookalikeScore scores a finding about a lookalike handle: an account that
+// exists may be impersonating the target, a free handle is only a name the
+// target could register defensively
+func (e *ScoringEngine) lookalikeScore(finding *models.Finding, weight float64) float64 {
+	switch finding.Status {
+	case models.StatusExists, models.StatusPrivate, models.StatusAbandoned:
+		finding.Severity = e.lookalike[finding.Indicator]
+		// A handle or display name indistinguishable from the brand is
+		// more likely to fool its audience
+		if score, ok := similarity.Of(*finding); ok && score >= similarity.NearIdentical {
+			finding.Severity = raise(finding.Severity)
+			weight *= 1.5
+		}
+		// and an account with a following or a badge reaches more people
+		if p := finding.Profile; p != nil && (p.Verified || p.Followers != nil && *p.Followers >= largeAudience) {
+			finding.Severity = raise(finding.Severity)
+			weight *= 1.5
+		}
+		// Wearing the brand's logo is a deliberate attempt to pass for it
+		if match, _ := finding.Metadata["avatar_match"].(bool); match {
+			finding.Severity = raise(finding.Severity)
+			weight *= 1.5
+		}
+		return weight
+	case models.StatusSuspended, models.StatusDeactivated, models.StatusRestricted:
+		finding.Severity = models.SeverityLow
+		return weight * 0.2
+	default:
+		finding.Severity = models.SeverityInfo
+		return 0
+	}
+}
+
+// raise returns the severity one level above s
+func raise(s models.Severity) models.Severity {
+	switch s {
+	case models.SeverityInfo:
+		return models.SeverityLow
+	case models.SeverityLow:
+		return models.SeverityMedium
+	case models.SeverityMedium:
+		return models.SeverityHigh
+	default:
+		return models.SeverityCritical
+	}
+}
+
 // GetOverallSeverity returns the highest severity level found
 func (e *ScoringEngine) GetOverallSeverity(result *models.ScanResult) models.Severity {
 	maxSeverity := models.SeverityInfo
@@ -73,11 +157,11 @@ func (e *ScoringEngine) GetOverallSeverity(result *models.ScanResult) models.Sev
 		models.SeverityCritical: 4,
 	}
 
-	for _, f := range result.Findings {
+	result.EachFinding(func(f *models.Finding) {
 		if severityMap[f.Severity] > severityMap[maxSeverity] {
 			maxSeverity = f.Severity
 		}
-	}
+	})
 
 	return maxSeverity
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/similarity"
 )
 
 func TestScoringEngine_Calculate(t *testing.T) {
@@ -33,6 +34,46 @@ func TestScoringEngine_Calculate(t *testing.T) {
 			},
 			expected: 30.0, // weight 15 * 2.0
 		},
+		{
+			name: "Inconclusive checks",
+			findings: []models.Finding{
+				{Indicator: "twitter_profile", Status: models.StatusUnknown},
+				{Indicator: "github_profile", Status: models.StatusRateLimited},
+				{Indicator: "check_failure", Status: models.StatusError},
+			},
+			expected: 0.0,
+		},
+		{
+			name: "Broken social link",
+			findings: []models.Finding{
+				{Indicator: "twitter_profile", Status: models.StatusAvailable},
+				{Indicator: "broken_social_link", Status: models.StatusAvailable},
+			},
+			expected: 80.0, // 15 * 2.0 + 25 * 2.0
+		},
+		{
+			name: "Impersonation candidates",
+			findings: []models.Finding{
+				{Indicator: "impersonation_candidate", Status: models.StatusExists},
+				{Indicator: "impersonation_candidate", Status: models.StatusAvailable},
+				{Indicator: "impersonation_candidate", Status: models.StatusSuspended},
+			},
+			expected: 9.6, // 8 for the existing lookalike + 8 * 0.2, free handles carry no risk
+		},
+		{
+			name: "Deactivated profile",
+			findings: []models.Finding{
+				{Indicator: "instagram_profile", Status: models.StatusDeactivated},
+			},
+			expected: 6.0, // weight 12 * 0.5
+		},
+		{
+			name: "Abandoned official profile",
+			findings: []models.Finding{
+				{Indicator: "twitter_profile", Status: models.StatusAbandoned},
+			},
+			expected: 15.0, // weight 15
+		},
 	}
 
 	for _, tt := range tests {
@@ -48,6 +89,61 @@ func TestScoringEngine_Calculate(t *testing.T) {
 	}
 }
 
+func TestScoringEngine_CriticalIndicator(t *testing.T) {
+	scorer := NewScoringEngine()
+	result := &models.ScanResult{
+		Findings: []models.Finding{{Indicator: "broken_social_link", Status: models.StatusAvailable}},
+	}
+	scorer.Calculate(result)
+	if got := scorer.GetOverallSeverity(result); got != models.SeverityCritical {
+		t.Errorf("GetOverallSeverity() = %v, want %v", got, models.SeverityCritical)
+	}
+}
+
+func TestScoringEngine_LookalikeSeverity(t *testing.T) {
+	scorer := NewScoringEngine()
+	followers := int64(25000)
+	result := &models.ScanResult{
+		Findings: []models.Finding{
+			{Indicator: "impersonation_candidate", Status: models.StatusAvailable},
+			{Indicator: "impersonation_candidate", Status: models.StatusExists},
+			{Indicator: "homoglyph_impersonation", Status: models.StatusExists},
+			{Indicator: "impersonation_candidate", Status: models.StatusExists, Metadata: map[string]interface{}{
+				"similarity": similarity.Score{Overall: 0.95},
+			}},
+			{Indicator: "impersonation_candidate", Status: models.StatusExists, Profile: &models.Profile{Followers: &followers}},
+			{Indicator: "impersonation_candidate", Status: models.StatusExists, Metadata: map[string]interface{}{"avatar_match": true}},
+			{Indicator: "impersonation_candidate", Status: models.StatusExists, Metadata: map[string]interface{}{"avatar_match": false}},
+			{Indicator: "homoglyph_impersonation", Status: models.StatusAbandoned},
+		},
+	}
+	scorer.Calculate(result)
+	if got := result.Findings[0].Severity; got != models.SeverityInfo {
+		t.Errorf("available lookalike severity = %v, want %v", got, models.SeverityInfo)
+	}
+	if got := result.Findings[1].Severity; got != models.SeverityMedium {
+		t.Errorf("existing lookalike severity = %v, want %v", got, models.SeverityMedium)
+	}
+	if got := result.Findings[2].Severity; got != models.SeverityHigh {
+		t.Errorf("existing homoglyph severity = %v, want %v", got, models.SeverityHigh)
+	}
+	if got := result.Findings[3].Severity; got != models.SeverityHigh {
+		t.Errorf("near-identical lookalike severity = %v, want %v", got, models.SeverityHigh)
+	}
+	if got := result.Findings[4].Severity; got != models.SeverityHigh {
+		t.Errorf("lookalike with a large audience severity = %v, want %v", got, models.SeverityHigh)
+	}
+	if got := result.Findings[5].Severity; got != models.SeverityHigh {
+		t.Errorf("lookalike wearing the brand logo severity = %v, want %v", got, models.SeverityHigh)
+	}
+	if got := result.Findings[6].Severity; got != models.SeverityMedium {
+		t.Errorf("lookalike with another avatar severity = %v, want %v", got, models.SeverityMedium)
+	}
+	if got := result.Findings[7].Severity; got != models.SeverityHigh {
+		t.Errorf("abandoned homoglyph severity = %v, want %v", got, models.SeverityHigh)
+	}
+}
+
 func TestScoringEngine_GetOverallSeverity(t *testing.T) {
 	scorer := NewScoringEngine()
 
@@ -63,3 +159,31 @@ func TestScoringEngine_GetOverallSeverity(t *testing.T) {
 		t.Errorf("GetOverallSeverity() = %v, want %v", got, models.SeverityHigh)
 	}
 }
+
+func TestScoringEngine_CalculateIdentities(t *testing.T) {
+	scorer := NewScoringEngine()
+
+	result := &models.ScanResult{
+		Findings: []models.Finding{
+			{Indicator: "github_profile", Status: models.StatusExists},
+		},
+		Identities: []models.Identity{
+			{Username: "acme", Findings: []models.Finding{
+				{Indicator: "twitter_profile", Status: models.StatusAvailable},
+			}},
+			{Username: "acme_hq", Findings: []models.Finding{
+				{Indicator: "instagram_profile", Status: models.StatusDeactivated},
+			}},
+		},
+	}
+
+	if got, want := scorer.Calculate(result), 38.0; got != want {
+		t.Errorf("Calculate() = %v, want %v", got, want)
+	}
+	if got := result.Identities[0].Findings[0].Severity; got != models.SeverityHigh {
+		t.Errorf("identity finding severity = %v, want %v", got, models.SeverityHigh)
+	}
+	if got := scorer.GetOverallSeverity(result); got != models.SeverityHigh {
+		t.Errorf("GetOverallSeverity() = %v, want %v", got, models.SeverityHigh)
+	}
+}
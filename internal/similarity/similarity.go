@@ -0,0 +1,217 @@
+// Package similarity measures how closely a handle or display name imitates
+// a protected brand, with edit distances, Jaro-Winkler, keyboard distance and
+// the confusable skeleton.
+package similarity
+
+import (
+	"math"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/confusables"
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+// NearIdentical is the score from which a lookalike is considered
+// indistinguishable from the brand at a glance
+const NearIdentical = 0.9
+
+// Score holds the similarity of a name to the brand by every measure, each
+// from 0 (unrelated) to 1 (identical)
+type Score struct {
+	Levenshtein float64 `json:"levenshtein"`
+	Damerau     float64 `json:"damerau"`
+	JaroWinkler float64 `json:"jaro_winkler"`
+	Keyboard    float64 `json:"keyboard"`
+	Skeleton    bool    `json:"skeleton"` // renders identically to the brand
+	Overall     float64 `json:"overall"`  // mean of the measures, 1 when the skeletons match
+}
+
+// Compare scores how closely name imitates brand, ignoring case
+func Compare(brand, name string) Score {
+	a, b := []rune(strings.ToLower(brand)), []rune(strings.ToLower(name))
+	n := float64(max(len(a), len(b)))
+	if n == 0 {
+		return Score{Levenshtein: 1, Damerau: 1, JaroWinkler: 1, Keyboard: 1, Skeleton: true, Overall: 1}
+	}
+
+	s := Score{
+		Levenshtein: 1 - float64(Levenshtein(brand, name))/n,
+		Damerau:     1 - float64(Damerau(brand, name))/n,
+		JaroWinkler: JaroWinkler(brand, name),
+		Keyboard:    1 - Keyboard(brand, name)/n,
+		Skeleton:    confusables.Skeleton(brand) == confusables.Skeleton(name),
+	}
+	// Jaro-Winkler alone rates any name sharing the brand's prefix highly,
+	// the edit distances keep it in check
+	s.Overall = (s.Levenshtein + s.Damerau + s.JaroWinkler + s.Keyboard) / 4
+	if s.Skeleton {
+		s.Overall = 1
+	}
+	s.Levenshtein, s.Damerau, s.JaroWinkler, s.Keyboard, s.Overall = round(s.Levenshtein), round(s.Damerau), round(s.JaroWinkler), round(s.Keyboard), round(s.Overall)
+	return s
+}
+
+// Levenshtein returns the number of insertions, deletions and substitutions
+// turning a into b, ignoring case
+func Levenshtein(a, b string) int {
+	return int(editDistance([]rune(strings.ToLower(a)), []rune(strings.ToLower(b)), false, unitCost))
+}
+
+// Damerau returns the Levenshtein distance with swaps of adjacent characters
+// counted as one edit (optimal string alignment), ignoring case
+func Damerau(a, b string) int {
+	return int(editDistance([]rune(strings.ToLower(a)), []rune(strings.ToLower(b)), true, unitCost))
+}
+
+// Keyboard returns the edit distance where substituting a key for one next to
+// it on a QWERTY keyboard, the typical typo, costs half an edit
+func Keyboard(a, b string) float64 {
+	return editDistance([]rune(strings.ToLower(a)), []rune(strings.ToLower(b)), true, keyCost)
+}
+
+func unitCost(x, y rune) float64 {
+	if x == y {
+		return 0
+	}
+	return 1
+}
+
+func keyCost(x, y rune) float64 {
+	if x == y {
+		return 0
+	}
+	px, okx := keys[x]
+	py, oky := keys[y]
+	if okx && oky && math.Abs(px[0]-py[0]) <= 1 && math.Abs(px[1]-py[1]) <= 1 {
+		return 0.5
+	}
+	return 1
+}
+
+// editDistance computes a weighted edit distance, with transpositions of
+// adjacent characters when swaps is set
+func editDistance(a, b []rune, swaps bool, cost func(x, y rune) float64) float64 {
+	d := make([][]float64, len(a)+1)
+	for i := range d {
+		d[i] = make([]float64, len(b)+1)
+		d[i][0] = float64(i)
+	}
+	for j := range d[0] {
+		d[0][j] = float64(j)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost(a[i-1], b[j-1]))
+			if swaps && i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, ignoring case:
+// the Jaro similarity boosted for a common prefix of up to four characters
+func JaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	if len(s1) == 0 && len(s2) == 0 {
+		return 1
+	}
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	window = max(window, 0)
+	m1, m2 := make([]bool, len(s1)), make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		for j := max(0, i-window); j < min(len(s2), i+window+1); j++ {
+			if !m2[j] && s1[i] == s2[j] {
+				m1[i], m2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, k := 0, 0
+	for i := range s1 {
+		if !m1[i] {
+			continue
+		}
+		for !m2[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// keys holds the row and column of every key of a QWERTY keyboard, rows
+// offset the way they are staggered
+var keys = func() map[rune][2]float64 {
+	rows := []struct {
+		keys   string
+		offset float64
+	}{
+		{"1234567890-", 0},
+		{"qwertyuiop", 0.5},
+		{"asdfghjkl", 0.75},
+		{"zxcvbnm", 1.25},
+	}
+	m := make(map[rune][2]float64)
+	for r, row := range rows {
+		for c, k := range row.keys {
+			m[k] = [2]float64{float64(r), float64(c) + row.offset}
+		}
+	}
+	return m
+}()
+
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+// Annotate stores in every lookalike finding of an identity, those naming
+// the brand they imitate in "impersonation_of", how closely its handle and
+// the display name the platform reported, if any, imitate the brand
+func Annotate(id *models.Identity) {
+	for i := range id.Findings {
+		f := &id.Findings[i]
+		brand, ok := f.Metadata["impersonation_of"].(string)
+		if !ok {
+			continue
+		}
+		f.SetMeta("similarity", Compare(brand, id.Username))
+		if f.Profile != nil && f.Profile.DisplayName != "" {
+			f.SetMeta("display_name_similarity", Compare(brand, f.Profile.DisplayName))
+		}
+	}
+}
+
+// Of returns the highest similarity recorded on a finding, of its handle or
+// its display name
+func Of(f models.Finding) (float64, bool) {
+	best, ok := 0.0, false
+	for _, key := range []string{"similarity", "display_name_similarity"} {
+		if s, found := f.Metadata[key].(Score); found {
+			best, ok = max(best, s.Overall), true
+		}
+	}
+	return best, ok
+}
@@ -0,0 +1,108 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestDistances(t *testing.T) {
+	tests := []struct {
+		a, b        string
+		levenshtein int
+		damerau     int
+		keyboard    float64
+	}{
+		{a: "kitten", b: "sitting", levenshtein: 3, damerau: 3, keyboard: 3},
+		{a: "acme", b: "amce", levenshtein: 2, damerau: 1, keyboard: 1},
+		{a: "acme", b: "acne", levenshtein: 1, damerau: 1, keyboard: 0.5}, // n is next to m
+		{a: "acme", b: "acpe", levenshtein: 1, damerau: 1, keyboard: 1},
+		{a: "Acme", b: "ACME"},
+		{a: "", b: "acme", levenshtein: 4, damerau: 4, keyboard: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Levenshtein(tt.a, tt.b); got != tt.levenshtein {
+				t.Errorf("Levenshtein() = %d, want %d", got, tt.levenshtein)
+			}
+			if got := Damerau(tt.a, tt.b); got != tt.damerau {
+				t.Errorf("Damerau() = %d, want %d", got, tt.damerau)
+			}
+			if got := Keyboard(tt.a, tt.b); got != tt.keyboard {
+				t.Errorf("Keyboard() = %v, want %v", got, tt.keyboard)
+			}
+		})
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "martha", b: "marhta", want: 0.961},
+		{a: "dwayne", b: "duane", want: 0.84},
+		{a: "dixon", b: "dicksonx", want: 0.813},
+		{a: "acme", b: "acme", want: 1},
+		{a: "abc", b: "xyz", want: 0},
+		{a: "", b: "", want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("JaroWinkler(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name          string
+		brand, handle string
+		nearIdentical bool
+		skeleton      bool
+	}{
+		{name: "Homoglyphs", brand: "acme", handle: "асmе", nearIdentical: true, skeleton: true},
+		{name: "Digit lookalike", brand: "paypal", handle: "paypa1", nearIdentical: true, skeleton: true},
+		{name: "Keyboard typo in a long brand", brand: "microsoft", handle: "microsift", nearIdentical: true},
+		{name: "Affix", brand: "acme", handle: "acmeofficial"},
+		{name: "Unrelated", brand: "acme", handle: "globex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Compare(tt.brand, tt.handle)
+			if (s.Overall >= NearIdentical) != tt.nearIdentical {
+				t.Errorf("Compare() overall = %v, want near-identical %v", s.Overall, tt.nearIdentical)
+			}
+			if s.Skeleton != tt.skeleton {
+				t.Errorf("Compare() skeleton = %v, want %v", s.Skeleton, tt.skeleton)
+			}
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	id := models.Identity{
+		Username: "acme_hq",
+		Findings: []models.Finding{
+			{PluginName: "Twitter", Metadata: map[string]interface{}{"impersonation_of": "acme"}, Profile: &models.Profile{DisplayName: "Acme"}},
+			{PluginName: "GitHub", Metadata: map[string]interface{}{"url": "https://github.com/acme_hq"}},
+		},
+	}
+
+	Annotate(&id)
+
+	handle, ok := id.Findings[0].Metadata["similarity"].(Score)
+	if !ok || handle.Overall >= NearIdentical {
+		t.Errorf("handle similarity = %+v, want a partial match", id.Findings[0].Metadata["similarity"])
+	}
+	if got, ok := Of(id.Findings[0]); !ok || got != 1 {
+		t.Errorf("Of() = %v, %v, want the identical display name's 1", got, ok)
+	}
+	if _, ok := Of(id.Findings[1]); ok {
+		t.Error("Annotate() scored a finding that is not about a lookalike")
+	}
+}
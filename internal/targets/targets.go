@@ -0,0 +1,165 @@
+package targets
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+	"github.com/ismailtsdln/socialrecon/internal/scanner"
+)
+
+// Classify guesses whether a value is a domain/URL or a username
+func Classify(value string) models.TargetType {
+	if strings.Contains(value, ".") || strings.HasPrefix(value, "http") {
+		return models.TargetDomain
+	}
+	return models.TargetUsername
+}
+
+// New builds a target, classifying it when no type is given. A profile
+// link such as https://x.com/acme given without a type is the username it
+// points to.
+func New(value string, typ models.TargetType) models.Target {
+	value = strings.TrimSpace(value)
+	if typ == "" || typ == models.TargetAuto {
+		if id, ok := scanner.Resolve(value); ok && strings.ContainsAny(value, "./") {
+			return models.Target{Value: id.Username, Type: models.TargetUsername}
+		}
+		typ = Classify(value)
+	}
+	return models.Target{Value: value, Type: typ}
+}
+
+// Parse reads targets from r. The input is either one target per line or
+// CSV with a target column and an optional type column (domain, username or
+// auto). It is read as CSV when it starts with a header row naming the
+// columns or when any line holds a comma. Blank lines and lines starting
+// with # are ignored; errors report the line numbers of the original input.
+func Parse(r io.Reader) ([]models.Target, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "#") {
+			line = "" // blanked rather than dropped to keep line numbers
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	first := slices.IndexFunc(lines, func(l string) bool { return l != "" })
+	if first == -1 {
+		return nil, nil
+	}
+	if isHeader(strings.Split(lines[first], ",")) || slices.ContainsFunc(lines, func(l string) bool { return strings.Contains(l, ",") }) {
+		return parseCSV(strings.Join(lines, "\n"))
+	}
+
+	var list []models.Target
+	for _, l := range lines {
+		if l != "" {
+			list = append(list, New(l, models.TargetAuto))
+		}
+	}
+	return list, nil
+}
+
+func parseCSV(data string) ([]models.Target, error) {
+	cr := csv.NewReader(strings.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var (
+		list               []models.Target
+		targetCol, typeCol = 0, 1
+	)
+	for n := 0; ; n++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		if n == 0 && isHeader(rec) {
+			targetCol, typeCol = -1, -1
+			for i, h := range rec {
+				switch strings.ToLower(strings.TrimSpace(h)) {
+				case "target", "value", "username", "domain":
+					if targetCol == -1 {
+						targetCol = i
+					}
+				case "type", "target_type", "kind":
+					typeCol = i
+				}
+			}
+			if targetCol == -1 {
+				return nil, fmt.Errorf("line %d: CSV header has no target column", line)
+			}
+			continue
+		}
+
+		if targetCol >= len(rec) || strings.TrimSpace(rec[targetCol]) == "" {
+			continue
+		}
+		typ := models.TargetAuto
+		if typeCol >= 0 && typeCol < len(rec) {
+			t, err := parseType(rec[typeCol])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			typ = t
+		}
+		list = append(list, New(rec[targetCol], typ))
+	}
+	return list, nil
+}
+
+func isHeader(rec []string) bool {
+	for _, f := range rec {
+		switch strings.ToLower(strings.TrimSpace(f)) {
+		case "target", "value", "type", "target_type":
+			return true
+		}
+	}
+	return false
+}
+
+func parseType(s string) (models.TargetType, error) {
+	switch t := models.TargetType(strings.ToLower(strings.TrimSpace(s))); t {
+	case "", models.TargetAuto:
+		return models.TargetAuto, nil
+	case models.TargetDomain, models.TargetUsername:
+		return t, nil
+	case "url", "website":
+		return models.TargetDomain, nil
+	case "handle", "brand":
+		return models.TargetUsername, nil
+	default:
+		return "", fmt.Errorf("unknown target type %q", s)
+	}
+}
+
+// Dedupe removes repeated targets, keeping the first occurrence
+func Dedupe(list []models.Target) []models.Target {
+	seen := make(map[models.Target]bool, len(list))
+	out := list[:0:0]
+	for _, t := range list {
+		key := models.Target{Value: strings.ToLower(t.Value), Type: t.Type}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, t)
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package targets
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ismailtsdln/socialrecon/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []models.Target
+	}{
+		{
+			name:  "One per line",
+			input: "acme\n# comment\n\nacme.com\nhttps://example.org\nhttps://x.com/AcmeHQ\n",
+			want: []models.Target{
+				{Value: "acme", Type: models.TargetUsername},
+				{Value: "acme.com", Type: models.TargetDomain},
+				{Value: "https://example.org", Type: models.TargetDomain},
+				{Value: "acmehq", Type: models.TargetUsername},
+			},
+		},
+		{
+			name:  "CSV with header",
+			input: "type,target\nusername,acme.hq\ndomain,acme.com\n,johndoe\n",
+			want: []models.Target{
+				{Value: "acme.hq", Type: models.TargetUsername},
+				{Value: "acme.com", Type: models.TargetDomain},
+				{Value: "johndoe", Type: models.TargetUsername},
+			},
+		},
+		{
+			name:  "CSV without header",
+			input: "acme.hq, username\nacme.com,domain\n",
+			want: []models.Target{
+				{Value: "acme.hq", Type: models.TargetUsername},
+				{Value: "acme.com", Type: models.TargetDomain},
+			},
+		},
+		{
+			name:  "CSV after a plain first line",
+			input: "acme\nacme.com,domain\n",
+			want: []models.Target{
+				{Value: "acme", Type: models.TargetUsername},
+				{Value: "acme.com", Type: models.TargetDomain},
+			},
+		},
+		{
+			name:  "Single column CSV with header",
+			input: "# brands\ntarget\nacme\nacme.com\n",
+			want: []models.Target{
+				{Value: "acme", Type: models.TargetUsername},
+				{Value: "acme.com", Type: models.TargetDomain},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_UnknownType(t *testing.T) {
+	_, err := Parse(strings.NewReader("# targets\ntarget,type\n\nacme,username\nacme.com,planet\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 5:") {
+		t.Errorf("Parse() error = %v, want an unknown type error on line 5", err)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	got := Dedupe([]models.Target{
+		{Value: "Acme", Type: models.TargetUsername},
+		{Value: "acme", Type: models.TargetUsername},
+		{Value: "acme", Type: models.TargetDomain},
+	})
+	if len(got) != 2 {
+		t.Errorf("Dedupe() = %v, want 2 targets", got)
+	}
+}